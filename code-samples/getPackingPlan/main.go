@@ -0,0 +1,121 @@
+package main
+
+/* getPackingPlan returns the packing plan assumed by the shipping quote for an order's store.Shipment.
+   Admin staff use the plan to reproduce the packing of each Package when fulfilling the order.
+   The plan is returned as a layer-by-layer SVG diagram of one Package (format=svg), or as a
+   3D scene of every Package for use by 3D viewers (format=json, default).
+   The shipment's plan is returned by default; the plan quoted for another carrier is returned if the
+   carrier is given (carrier=ups).
+   Diagrams are rendered by the packops package.
+*/
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/apex/gateway"
+	"github.com/ggarcia209/acamoprjct/service/util/dbops"
+	"github.com/ggarcia209/acamoprjct/service/util/httpops"
+	"github.com/ggarcia209/acamoprjct/service/util/packops"
+)
+
+const route = "/admin/orders/packing_plan" // GET ?order_id=&format=svg|json&parcel=&carrier=
+
+const failMsg = "Request failed!"
+const successMsg = "Request succeeded!"
+
+// list of tables function makes r/w calls to
+var tables = []dbops.Table{
+	dbops.Table{
+		Name:       dbops.ShipmentsTable(),
+		PrimaryKey: dbops.ShipmentsPK,
+	},
+}
+
+// RootHandler handles HTTP request
+func RootHandler(w http.ResponseWriter, r *http.Request) {
+	// DB is used to make DynamoDB API calls
+	DB := dbops.InitDB(tables)
+
+	q := r.URL.Query()
+	orderID := q.Get("order_id")
+	if orderID == "" {
+		log.Printf("bad request - empty keys")
+		httpops.ErrResponse(w, "Bad Request: empty order keys", failMsg, http.StatusBadRequest)
+		return
+	}
+
+	format := q.Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "svg" {
+		httpops.ErrResponse(w, "Bad Request: invalid format "+format, failMsg, http.StatusBadRequest)
+		return
+	}
+
+	// get shipment packages
+	shipment, err := dbops.GetShipment(DB, orderID)
+	if err != nil {
+		log.Printf("RootHandler failed - getShipment: %v", err)
+		httpops.ErrResponse(w, "Internal Server Error: "+err.Error(), failMsg, http.StatusInternalServerError)
+		return
+	}
+	if shipment.OrderID == "" {
+		httpops.ErrResponse(w, "Not Found: no shipment for order "+orderID, failMsg, http.StatusNotFound)
+		return
+	}
+
+	// packages of the carrier's plan; the shipment's plan by default
+	pkgs := shipment.Packages
+	if carrier := q.Get("carrier"); carrier != "" {
+		plan, ok := shipment.Plans[carrier]
+		if !ok {
+			httpops.ErrResponse(w, "Not Found: no packing plan for carrier "+carrier, failMsg, http.StatusNotFound)
+			return
+		}
+		pkgs = plan.Packages
+	}
+
+	if format == "json" {
+		scene, err := packops.RenderScene(pkgs)
+		if err != nil {
+			log.Printf("RootHandler failed - renderScene: %v", err)
+			httpops.ErrResponse(w, "Internal Server Error: "+err.Error(), failMsg, http.StatusInternalServerError)
+			return
+		}
+		httpops.ErrResponse(w, "Packing plan: ", scene, http.StatusOK)
+		return
+	}
+
+	// svg diagram of a single package; first package by default
+	index := 0
+	if s := q.Get("parcel"); s != "" {
+		index, err = strconv.Atoi(s)
+		if err != nil || index < 0 || index >= len(pkgs) {
+			httpops.ErrResponse(w, "Bad Request: invalid parcel index "+s, failMsg, http.StatusBadRequest)
+			return
+		}
+	}
+	if len(pkgs) == 0 {
+		httpops.ErrResponse(w, "Not Found: shipment has no packages", failMsg, http.StatusNotFound)
+		return
+	}
+
+	svg, err := packops.RenderSVG(pkgs[index])
+	if err != nil {
+		log.Printf("RootHandler failed - renderSVG: %v", err)
+		httpops.ErrResponse(w, "Internal Server Error: "+err.Error(), failMsg, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(svg))
+	return
+}
+
+func main() {
+	httpops.RegisterRoutes(route, RootHandler)
+	log.Fatal(gateway.ListenAndServe(":3000", nil))
+}
@@ -7,6 +7,30 @@ package main
    optimized by using the smallest available package that will fit the whole order. If multiple
    packages are required, the largest available parcel will be used to package the order, until
    there is a smaller parcel that can fit the remaining order volume.
+   The packing algorithm is implemented by the packops package.
+
+   Rates are quoted for each carrier listed in the SHIPPING_CARRIERS environment variable (comma separated
+   shippo carrier tokens, e.g. "usps,ups,fedex,dhl_express"; USPS only by default). Each carrier's parcels
+   are loaded from its "parcels-<carrier>" index, the order is packed separately for each carrier, and the
+   rates of every carrier are returned as one list sorted by price. Each carrier's packing plan is stored in
+   the shipment's Plans by carrier; the shipment's Packages hold the plan of the carrier quoting the cheapest
+   rate until a label is purchased for the selected rate.
+
+   Addresses, parcels, and shipments are created through the carrier-neutral rateops.RateProvider
+   interface. The shipping API is selected by the SHIPPING_API environment variable: "shippo" (default)
+   or "easypost". The API key of each is read from disk. Each carrier's candidate packing plans are ranked
+   offline with the rate tables (packops.DefaultTableRates, which has tiers for each carrier, if none apply),
+   and only the selected plan is quoted by the shipping API.
+
+   If the shipping API is unavailable or fails to quote the order, rates are estimated offline from the
+   rate tables stored in the RateTables table (weight & zone tiers for each service level), so customers
+   can still check out. Estimated rates are marked as such on the stored shipment, and are re-rated with
+   the shipping API when the label is purchased. Invalid shipping addresses are not estimated, and a
+   packing plan failing verification (packops.PackingError) fails the request with a 500.
+
+   Shipping promotions stored in the ShippingRules table are applied to the sorted rates by the promoops
+   package, and the rates are re-sorted by charged price; each stored rate records both its quoted and
+   charged price. Rates are returned without promotions if the rules can not be loaded.
 */
 
 import (
@@ -15,15 +39,18 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"strconv"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/apex/gateway"
 	"github.com/coldbrewcloud/go-shippo"
-	"github.com/coldbrewcloud/go-shippo/client"
-	"github.com/coldbrewcloud/go-shippo/models"
 	"github.com/ggarcia209/acamoprjct/service/store-api/store"
 	"github.com/ggarcia209/acamoprjct/service/util/dbops"
 	"github.com/ggarcia209/acamoprjct/service/util/httpops"
+	"github.com/ggarcia209/acamoprjct/service/util/packops"
+	"github.com/ggarcia209/acamoprjct/service/util/promoops"
+	"github.com/ggarcia209/acamoprjct/service/util/rateops"
 	"github.com/ggarcia209/acamoprjct/service/util/shipops"
 	"github.com/ggarcia209/acamoprjct/service/util/sortops"
 	"github.com/ggarcia209/go-aws/go-dynamo/dynamo"
@@ -34,6 +61,18 @@ const route = "/store/checkout/get_rates" // PUT
 const failMsg = "Request failed!"
 const successMsg = "Request succeeded!"
 
+// envarCarriers contains the comma separated carrier tokens rates are quoted for
+const envarCarriers = "SHIPPING_CARRIERS"
+
+// envarShippingAPI contains the shipping API used to quote rates
+const envarShippingAPI = "SHIPPING_API"
+
+// shipping APIs
+const (
+	apiShippo   = "shippo"
+	apiEasyPost = "easypost"
+)
+
 // list of tables function makes r/w calls to
 var tables = []dbops.Table{
 	dbops.Table{
@@ -57,6 +96,14 @@ var tables = []dbops.Table{
 		Name:       dbops.ShipmentsTable(),
 		PrimaryKey: dbops.ShipmentsPK,
 	},
+	dbops.Table{
+		Name:       dbops.RateTablesTable(),
+		PrimaryKey: dbops.RateTablesPK,
+	},
+	dbops.Table{
+		Name:       dbops.ShippingRulesTable(),
+		PrimaryKey: dbops.ShippingRulesPK,
+	},
 }
 
 // customerInfo represents the request info submitted from the /store/checkout/shipping page
@@ -65,110 +112,6 @@ type customerInfo struct {
 	OrderID string `json:"order_id"`
 }
 
-// getDimensions() return type
-type dimensions struct {
-	Weight    float32
-	Volume    float32
-	MaxLength float32
-	MaxWidth  float32
-	MaxHeight float32
-}
-
-// Box represents a 3D cubic space and is a subcomponent of the dynamic programming model
-// for filling parcels with each item in an Order. Each filled Box contains 1 PkgItem
-// bordered by 3 child Box nodes along the PkgItem's X, Y, & Z axes, derived from its remaining 3D space.
-// The Root Box Node represents the selected shipping Parcel to fill with CartItem units
-// and the whole of it's volume.
-type Box struct {
-	Volume  float32
-	ResvPct float32 // percentage of parcel volume reserved for packing materials
-	Length  float32
-	Width   float32
-	Height  float32
-	Item    string
-	NodeL   *Box
-	NodeW   *Box
-	NodeH   *Box
-}
-
-// PkgItem represents an individual unit of a CartItem in an Order and the 3D space it occupies within a Box / Parcel (Box Root Node).
-// PkgItems are sorted by volume greatest to least, and are successively added to the child Nodes of a Box and their descendants
-// as remaining space permits.
-type PkgItem struct {
-	ItemID string
-	Name   string
-	Volume float32
-	Length float32
-	Width  float32
-	Height float32
-}
-
-// Add adds an item to the current Box, and creates 3 child Box nodes.
-// Each node represents the remaining space derived from the current Box
-// in the form of a smaller, empty Box formed along the X, Y, and Z axes of the item,
-// bounded by the dimensions of the current, occupied Box.
-// Each smaller box is recursively filled with the next largest item until there are no remaining items,
-// or there is an insufficient amount of space in the Root Box (node) for the remaining items.
-func (b *Box) Add(item PkgItem) error {
-	ok := true
-	resv := b.ResvPct + 1.0
-	// compare along y axis orientation (default)
-	if item.Length > (b.Length/resv) || item.Width > (b.Width/resv) || item.Height > (b.Height/resv) {
-		ok = false
-	}
-	if !ok {
-		// compare along x axis orientation
-		if item.Width > (b.Length/resv) || item.Length > (b.Width/resv) || item.Height > (b.Height/resv) {
-			ok = false
-		} else {
-			ok = true
-		}
-	}
-	if !ok {
-		// compare along z axis orientation
-		if item.Height > (b.Length/resv) || item.Length > (b.Width/resv) || item.Length > (b.Height/resv) {
-			ok = false
-		} else {
-			ok = true
-		}
-	}
-	if !ok {
-		// insufficient space
-		return fmt.Errorf("DIMENSIONS_EXCEEDED")
-	}
-
-	// add item to current box; create child nodes
-	b.Item = item.ItemID
-
-	// l = (Lx - Ly, Wy, Hx)
-	lBox := &Box{
-		Length: b.Length - item.Length,
-		Width:  item.Width,
-		Height: b.Height,
-	}
-	lBox.Volume = lBox.Length * lBox.Width * lBox.Height
-
-	// w = (Lx, Wx - Wy, Hx)
-	wBox := &Box{
-		Length: b.Length,
-		Width:  b.Width - item.Width,
-		Height: b.Height,
-	}
-	wBox.Volume = wBox.Length * wBox.Width * wBox.Height
-
-	// h = (Ly, Wy, Hx - Hy)
-	hBox := &Box{
-		Length: item.Length,
-		Width:  item.Width,
-		Height: b.Height - item.Height,
-	}
-	hBox.Volume = hBox.Length * hBox.Width * hBox.Height
-
-	b.NodeL, b.NodeW, b.NodeH = lBox, wBox, hBox
-
-	return nil
-}
-
 // RootHandler handles HTTP request
 func RootHandler(w http.ResponseWriter, r *http.Request) {
 	// DB is used to make DynamoDB API calls
@@ -203,14 +146,14 @@ func RootHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// initialize shippo client
-	token, err := getToken()
+	// initialize shipping API; rates are estimated with the rate tables if it is unavailable
+	rp, err := newRateProvider()
 	if err != nil {
-		log.Printf("RootHandler failed - getToken: %v", err)
-		httpops.ErrResponse(w, "Internal Server Error: "+err.Error(), failMsg, http.StatusInternalServerError)
-		return
+		log.Printf("RootHandler - newRateProvider: %v", err)
 	}
-	c := shippo.NewClient(token)
+
+	// initialize packing planner
+	pl := packops.NewPlanner(packops.NewPacker())
 
 	// get order items
 	order, err := dbops.GetOrder(DB, data.UserID, data.OrderID)
@@ -221,14 +164,16 @@ func RootHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// get shipping rates
-	rates, shipment, err := getShippingRates(DB, c, data, order)
+	rates, shipment, err := quoteShippingRates(DB, rp, pl, data, order)
 	if err != nil {
-		log.Printf("RootHandler failed - getShippingRates: %v", err)
+		log.Printf("RootHandler failed - quoteShippingRates: %v", err)
 		httpops.ErrResponse(w, "Internal Server Error: "+err.Error(), failMsg, http.StatusInternalServerError)
 		return
 	}
 
+	// promotions may change the order of the rates; re-sort by charged price
 	sorted := sortops.SortRatesByPrice(rates)
+	sorted = promoops.SortByPrice(applyPromotions(DB, order, sorted))
 	shipment.Rates = []store.RateSummary{}
 	for _, rate := range sorted {
 		shipment.Rates = append(shipment.Rates, *rate)
@@ -247,495 +192,393 @@ func RootHandler(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
-// get shippo API token from disk
-func getToken() (string, error) {
-	token, err := shipops.GetToken("./stk.txt")
+// apply the shipping promotion rules to the sorted rates; rates are returned unchanged if the rules can not be loaded
+func applyPromotions(DB *dynamo.DbInfo, order *store.Order, rates []*store.RateSummary) []*store.RateSummary {
+	rules, err := dbops.GetShippingRules(DB)
 	if err != nil {
-		log.Printf("getToken failed: %v", err)
-		return "", err
+		log.Printf("applyPromotions failed: %v", err)
+		return rates
 	}
-	return token, nil
+	return promoops.Apply(rules, promoCart(DB, order), rates)
 }
 
-// get shipping rates for order
-func getShippingRates(DB *dynamo.DbInfo, c *client.Client, data customerInfo, order *store.Order) ([]store.RateSummary, store.Shipment, error) {
-	// create to/from addresses
-	to, err := createShipmentAddress(c, order.ShippingAddress)
-	if err != nil {
-		log.Printf("getShippingRates failed: %v", err)
-		return nil, store.Shipment{}, err
+// promoCart returns the order info promotion rules are evaluated against
+// the customer is not a member if the customer record can not be loaded
+func promoCart(DB *dynamo.DbInfo, order *store.Order) promoops.Cart {
+	cart := promoops.Cart{
+		Subtotal: order.Subtotal,
+		Items:    order.Items,
+		Address:  order.ShippingAddress,
 	}
-
-	from, err := createReturnAddress(c)
+	customer, err := dbops.GetCustomer(DB, order.UserID)
 	if err != nil {
-		log.Printf("getShippingRates failed: %v", err)
-		return nil, store.Shipment{}, err
+		log.Printf("promoCart - getCustomer: %v", err)
+		return cart
 	}
+	cart.Member = customer.Member
+	return cart
+}
 
-	// create parcels
-	parcelIDs, err := dbops.GetStoreItemIndex(DB, "parcels-"+store.CarriersUsps) // fixed to USPS
-	if err != nil {
-		log.Printf("getShippingRates failed: %v", err)
-		return nil, store.Shipment{}, err
+// newRateProvider returns the RateProvider of the shipping API set in SHIPPING_API; shippo is used if not set
+func newRateProvider() (rateops.RateProvider, error) {
+	switch api := strings.ToLower(os.Getenv(envarShippingAPI)); api {
+	case "", apiShippo:
+		return newShippoProvider()
+	case apiEasyPost:
+		return newEasyPostProvider()
+	default:
+		log.Printf("newRateProvider failed - unknown shipping API: %s", api)
+		return nil, fmt.Errorf("INVALID_SHIPPING_API")
 	}
+}
 
-	parcelObjs, err := dbops.BatchGetParcels(DB, parcelIDs.ItemIDs)
+// newShippoProvider returns a shippo RateProvider with the API token from disk
+func newShippoProvider() (rateops.RateProvider, error) {
+	token, err := getToken()
 	if err != nil {
-		log.Printf("getShippingRates failed: %v", err)
-		return nil, store.Shipment{}, err
+		log.Printf("newShippoProvider failed: %v", err)
+		return nil, err
 	}
+	return rateops.NewShippo(shippo.NewClient(token)), nil
+}
 
-	parcels, packages, err := createParcels(c, order.Items, parcelObjs)
+// newEasyPostProvider returns an EasyPost RateProvider with the API key from disk
+func newEasyPostProvider() (rateops.RateProvider, error) {
+	key, err := shipops.GetToken("./ept.txt")
 	if err != nil {
-		log.Printf("getShippingRates failed: %v", err)
-		return nil, store.Shipment{}, err
+		log.Printf("newEasyPostProvider failed: %v", err)
+		return nil, err
 	}
+	return rateops.NewEasyPost(key), nil
+}
 
-	// create shipment objects and get rates
-	parcelIDinput := []string{}
-	for _, p := range parcels {
-		parcelIDinput = append(parcelIDinput, p.ObjectID)
+// get shippo API token from disk
+func getToken() (string, error) {
+	token, err := shipops.GetToken("./stk.txt")
+	if err != nil {
+		log.Printf("getToken failed: %v", err)
+		return "", err
 	}
+	return token, nil
+}
 
-	shipmentInput := &models.ShipmentInput{
-		AddressFrom: from.ObjectID,
-		AddressTo:   to.ObjectID,
-		Parcels:     parcelIDinput,
+// get shipping rates for order with the shipping API, or estimate them with the rate tables if the API
+// is unavailable (rp is nil) or fails; invalid shipping addresses are returned as errors and not estimated
+func quoteShippingRates(DB *dynamo.DbInfo, rp rateops.RateProvider, pl packops.Planner, data customerInfo, order *store.Order) ([]store.RateSummary, store.Shipment, error) {
+	if rp != nil {
+		rates, shipment, err := getShippingRates(DB, rp, pl, data, order)
+		if err == nil || !estimable(err) {
+			return rates, shipment, err
+		}
+		log.Printf("quoteShippingRates - shipping API failed; estimating rates: %v", err)
 	}
 
-	shipment, err := c.CreateShipment(shipmentInput)
+	tr, err := newTableRateProvider(DB)
 	if err != nil {
-		log.Printf("getShippingRates failed: %v", err)
+		log.Printf("quoteShippingRates failed: %v", err)
 		return nil, store.Shipment{}, err
 	}
+	return getShippingRates(DB, tr, pl, data, order)
+}
 
-	// return rates & object to store in DB for further actioning
-	shipmentDB := createShipmentObject(data, shipment, packages)
-
-	return shipmentDB.Rates, shipmentDB, nil
+// estimable returns true if rates may be estimated with the rate tables after quoting failed with err
+// invalid addresses and packing plans failing verification are returned to the customer instead
+func estimable(err error) bool {
+	var perr *packops.PackingError
+	return err.Error() != "INVALID_ADDRESS" && !errors.As(err, &perr)
 }
 
-// create shippo address object with customer info
-func createShipmentAddress(c *client.Client, data store.Address) (*models.Address, error) {
-	ai := &models.AddressInput{
-		Name:          data.FirstName + " " + data.LastName,
-		Company:       data.Company,
-		Street1:       data.AddressLine1,
-		Street2:       data.AddressLine2,
-		City:          data.City,
-		Zip:           data.Zip,
-		State:         data.State,
-		Country:       data.Country,
-		Phone:         data.PhoneNumber,
-		Email:         data.Email,
-		IsResidential: true,
-		Validate:      true,
-	}
-	// populate other fields if applicable
-	addr, err := c.CreateAddress(ai)
+// newTableRateProvider returns a RateProvider estimating rates offline with the rate tables from the DB
+func newTableRateProvider(DB *dynamo.DbInfo) (rateops.RateProvider, error) {
+	rts, err := dbops.GetRateTables(DB)
 	if err != nil {
-		log.Printf("createShipmentAddress failed: %v", err)
+		log.Printf("newTableRateProvider failed: %v", err)
 		return nil, err
 	}
-	log.Printf("validation result: %v; %v", addr.ValidationResults.IsValid, addr.ValidationResults.Messages)
-	if !addr.ValidationResults.IsValid {
-		return nil, fmt.Errorf("INVALID_ADDRESS")
+	if len(rts) == 0 {
+		log.Printf("newTableRateProvider failed: no rate tables")
+		return nil, fmt.Errorf("NO_RATE_TABLES")
 	}
-	return addr, nil
+	return rateops.NewTableRates(rts), nil
 }
 
-// create shippo address object with business info
-func createReturnAddress(c *client.Client) (*models.Address, error) {
-	data := store.ReturnAddress
-	ai := &models.AddressInput{
-		Name:          data.FirstName + " " + data.LastName,
-		Company:       data.Company,
-		Street1:       data.AddressLine1,
-		Street2:       data.AddressLine2,
-		City:          data.City,
-		Zip:           data.Zip,
-		State:         data.State,
-		Country:       data.Country,
-		Phone:         data.PhoneNumber,
-		Email:         data.Email,
-		IsResidential: true,
-		Validate:      false,
-	}
-	// populate other fields if applicable
-	addr, err := c.CreateAddress(ai)
+// get shipping rates for order from each enabled carrier
+// carriers that fail to quote are skipped; an error is returned if no carrier can quote the order
+// a packing plan failing verification fails the order for every carrier
+func getShippingRates(DB *dynamo.DbInfo, rp rateops.RateProvider, pl packops.Planner, data customerInfo, order *store.Order) ([]store.RateSummary, store.Shipment, error) {
+	// create to/from addresses
+	to, err := createShipmentAddress(rp, order.ShippingAddress)
 	if err != nil {
-		log.Printf("createShipmentAddress failed: %v", err)
-		return nil, err
+		log.Printf("getShippingRates failed: %v", err)
+		return nil, store.Shipment{}, err
 	}
 
-	return addr, nil
-}
+	from, err := createReturnAddress(rp)
+	if err != nil {
+		log.Printf("getShippingRates failed: %v", err)
+		return nil, store.Shipment{}, err
+	}
 
-// Create parcel(s) for order. Uses greedy algorithm for large multi-parcel orders to fit as many
-// objects into the largest parcel as possible (higher price : volume ratio) and fit the remainder in the smallest
-// parcel as possible and repeats as necessary for orders requring >2 parcels.
-func createParcels(c *client.Client, items []*store.CartItem, parcels []*store.Parcel) ([]*models.Parcel, []store.Package, error) {
-	parcelObjs := []*models.Parcel{}
-	packages := []store.Package{}
-	resVolPct := float32(0.2)
-
-	// split packages with greedy algorithm if total order volume is greater than largest parcel volume
-	// sort CartItems by volume greatest to least.
-	sortedByVol := sortops.SortCartItemsByUnitVolume(items)
-	// create package item for each individual unit in order
-	pkgItems := []PkgItem{}
-	for _, item := range sortedByVol {
-		for j := 0; j < item.Quantity; j++ {
-			// create PkgItem for each individual unit
-			pd := item.ShippingDimensions
-			floats, err := pd.GetFloatsMM()
-			if err != nil {
-				log.Printf("createParcels failed - get item floats: %v", err)
-				return parcelObjs, packages, err
-			}
-			pi := PkgItem{
-				ItemID: item.SizeID,
-				Name:   item.Name,
-				Length: floats[0],
-				Width:  floats[1],
-				Height: floats[2],
-				Volume: floats[0] * floats[1] * floats[2],
-			}
-			pkgItems = append(pkgItems, pi)
-		}
+	// rate tables rank each carrier's candidate packing plans offline
+	tables, tErr := dbops.GetRateTables(DB)
+	if tErr != nil {
+		log.Printf("getShippingRates - rate tables unavailable; ranking plans with default table rates: %v", tErr)
 	}
 
-	// create parcels for order until there is no remaining order volume
-	prevRem := 0
-	for {
-		// get parcel
-		parcel, packaged, rem, pack, err := getParcelForVolume(c, parcels, items, pkgItems, resVolPct)
-		if err != nil {
-			log.Printf("createParcels failed: %v", err)
-			return parcelObjs, packages, err
+	quotes := []carrierQuote{}
+	for _, carrier := range enabledCarriers(os.Getenv(envarCarriers)) {
+		q, qErr := quoteCarrier(DB, rp, pl, order, from, to, carrier, tables)
+		var perr *packops.PackingError
+		if errors.As(qErr, &perr) {
+			log.Printf("getShippingRates failed - %s packing plan failed verification: %v", carrier, qErr)
+			return nil, store.Shipment{}, qErr
 		}
-
-		// create package summary / add packing list to Package
-		for _, item := range pack {
-			if packaged.Items[item.ItemID] == nil {
-				itemSum := &store.PkgItemSummary{
-					ItemID:   item.ItemID,
-					Name:     item.Name,
-					Quantity: 1,
-				}
-				packaged.Items[item.ItemID] = itemSum
-			} else {
-				packaged.Items[item.ItemID].Quantity++
-			}
+		if qErr != nil {
+			log.Printf("getShippingRates - %s skipped: %v", carrier, qErr)
+			err = qErr
+			continue
 		}
+		quotes = append(quotes, q)
+	}
+	if len(quotes) == 0 {
+		log.Printf("getShippingRates failed: %v", err)
+		return nil, store.Shipment{}, err
+	}
 
-		parcelObjs = append(parcelObjs, parcel)
-		packages = append(packages, packaged)
+	// return rates & object to store in DB for further actioning
+	shipmentDB := createShipmentObject(data, quotes)
 
-		// return if complete order packaged
-		if len(rem) == 0 {
-			return parcelObjs, packages, nil
-		}
+	return shipmentDB.Rates, shipmentDB, nil
+}
 
-		pkgItems = rem
-		if len(rem) == prevRem {
-			// edge case - no parcel found in list for remaining items
-			return []*models.Parcel{}, []store.Package{}, fmt.Errorf("NO_PARCEL_FOUND")
-		}
-		prevRem = len(rem)
-	}
+// carrierQuote represents the packing plan and shipment created for a single carrier
+type carrierQuote struct {
+	carrier  string
+	to       rateops.Address
+	shipment rateops.Shipment
+	packages []store.Package
+	plan     packops.Plan
 }
 
-// get package dimensions required to fit order
-func getDimensions(items []*store.CartItem) (dimensions, error) {
-	totalWtLbs := float32(0.0)
-	totalVolume := float32(0.0)
-	maxLength := float32(0.0)
-	maxWidth := float32(0.0)
-	maxHeight := float32(0.0)
-
-	// calculate order volume
-	for _, item := range items {
-		// get volume and weight
-		floats, err := item.ShippingDimensions.GetFloatsMM()
-		if err != nil {
-			log.Printf("getDimensions failed: %v", err)
-			return dimensions{}, err
-		}
-		l, w, h, wt := floats[0], floats[1], floats[2], floats[3]
-		volume := l * w * h
-		totalWtLbs += (wt * float32(item.Quantity))
-		totalVolume += volume
-
-		// get max l, w, h
-		if l > maxLength {
-			maxLength = l
-		}
-		if w > maxWidth {
-			maxWidth = w
-		}
-		if h > maxHeight {
-			maxHeight = h
+// enabledCarriers returns the carrier tokens listed in the comma separated list,
+// or USPS if the list is empty
+func enabledCarriers(list string) []string {
+	carriers := []string{}
+	seen := make(map[string]bool)
+	for _, carrier := range strings.Split(list, ",") {
+		carrier = strings.ToLower(strings.TrimSpace(carrier))
+		if carrier == "" || seen[carrier] {
+			continue
 		}
-
-		// getWeightLbs()
+		seen[carrier] = true
+		carriers = append(carriers, carrier)
 	}
-
-	dim := dimensions{totalWtLbs, totalVolume, maxLength, maxWidth, maxHeight}
-
-	return dim, nil
+	if len(carriers) == 0 {
+		return []string{store.CarriersUsps}
+	}
+	return carriers
 }
 
-// get smallest parcel for order volume in cubic mm
-func getParcelForVolume(c *client.Client, parcels []*store.Parcel, cartItems []*store.CartItem, pkgItems []PkgItem, resvPct float32) (*models.Parcel, store.Package, []PkgItem, []PkgItem, error) {
-	rem := []PkgItem{}
-	pack := []PkgItem{}
-	sorted := sortops.SortParcelsByVolume(parcels) // sort by volume least to greatest
-	pkg := store.Package{}
-	pi := &models.ParcelInput{}
+// quotedFor returns true if the shippo service level token belongs to the carrier,
+// e.g. "usps_priority" for "usps" or "dhl_express_worldwide" for "dhl_express"
+func quotedFor(carrier, token string) bool {
+	return strings.HasPrefix(token, carrier+"_")
+}
 
-	if len(cartItems) == 0 || len(pkgItems) == 0 {
-		log.Printf("getParcelForVolume: no items")
-		return &models.Parcel{}, store.Package{}, rem, pack, nil
+// pack order in the carrier's parcels and create shipment for the packing plan
+// candidate plans are ranked offline with the rate tables; only the selected plan is quoted by the RateProvider
+func quoteCarrier(DB *dynamo.DbInfo, rp rateops.RateProvider, pl packops.Planner, order *store.Order, from, to rateops.Address, carrier string, tables []*store.RateTable) (carrierQuote, error) {
+	// create parcels
+	parcelIDs, err := dbops.GetStoreItemIndex(DB, "parcels-"+carrier)
+	if err != nil {
+		log.Printf("quoteCarrier failed: %v", err)
+		return carrierQuote{}, err
 	}
 
-	// get parcel dimension constraints from order in mm
-	dimensions, err := getDimensions(cartItems)
+	parcelObjs, err := dbops.BatchGetParcels(DB, parcelIDs.ItemIDs)
 	if err != nil {
-		log.Printf("getParcelForVolume failed: %v", err)
-		return &models.Parcel{}, store.Package{}, rem, pack, err
+		log.Printf("quoteCarrier failed: %v", err)
+		return carrierQuote{}, err
 	}
-	volume := dimensions.Volume
-	mL, mW, mH := dimensions.MaxLength, dimensions.MaxWidth, dimensions.MaxHeight
 
-	// search for available parcel to fit order volume and product dimension constraints
-	for _, p := range sorted {
-		floats, err := p.ParcelDimensions.GetFloatsMM()
-		if err != nil {
-			log.Printf("getParcelForVolume: no items")
-			return &models.Parcel{}, store.Package{}, rem, pack, err
-		}
-		l, w, h := floats[0], floats[1], floats[2]
-		parcelVol := l * w * h
-		if volume < float32(parcelVol*(1-resvPct)) { // leave extra space for packaging materials
-			// order volume ok - verify parcel dimensions fit largest items
-			// compare dimensions of largest items to dimensions of parcel in mm
-			l, w, h := floats[0], floats[1], floats[2]
-			if l < mL || w < mW || h < mH {
-				// parcel does not fit largest objects
-				continue
-			}
+	// rank packing plans with the carrier's table rates for the destination
+	est := &tableEstimator{
+		tables:   tables,
+		carrier:  carrier,
+		to:       to.Address,
+		fallback: packops.DefaultTableRates,
+	}
 
-			// fill parcel
-			rem, pack, err = fillParcel(pkgItems, p, float32(0.1))
-			if err != nil {
-				log.Printf("getParcelForVolume failed: %v", err)
-				return &models.Parcel{}, store.Package{}, rem, pack, err
-			}
+	parcels, packages, plan, err := createParcels(rp, pl, est, order.Items, parcelObjs, carrier)
+	if err != nil {
+		log.Printf("quoteCarrier failed: %v", err)
+		return carrierQuote{}, err
+	}
 
-			// get parcel wt
-			pWt, err := p.ParcelDimensions.GetWeightLb()
-			if err != nil {
-				log.Printf("getParcelForVolume failed: %v", err)
-				return &models.Parcel{}, store.Package{}, rem, pack, err
-			}
-			totalWt := pWt
-			inParcel := make(map[string]*store.CartItem)
-			for _, item := range cartItems {
-				inParcel[item.SizeID] = item
-			}
-			for _, item := range pack {
-				unitWt, err := inParcel[item.ItemID].ShippingDimensions.GetWeightLb()
-				if err != nil {
-					log.Printf("getParcelForVolume failed: %v", err)
-					return &models.Parcel{}, store.Package{}, rem, pack, err
-				}
-				totalWt += unitWt
-			}
+	// create shipment object and get rates
+	shipment, err := rp.CreateShipment(from, to, parcels)
+	if err != nil {
+		log.Printf("quoteCarrier failed: %v", err)
+		return carrierQuote{}, err
+	}
 
-			// create store.Package object for DB storage
-			pkg = store.Package{
-				Carrier:    p.Carrier,
-				ParcelID:   p.ParcelID,
-				Name:       p.Name,
-				Dimensions: p.ParcelDimensions,
-				Template:   p.Template,
-				Items:      make(map[string]*store.PkgItemSummary),
-			}
-			// create shippo parcel object
-			pi = &models.ParcelInput{
-				Length:       p.ParcelDimensions.Length,
-				Width:        p.ParcelDimensions.Width,
-				Height:       p.ParcelDimensions.Height,
-				DistanceUnit: p.ParcelDimensions.DistanceUnit,
-				Weight:       fmt.Sprintf("%.2f", totalWt),
-				MassUnit:     p.ParcelDimensions.MassUnit,
-			}
-			if len(rem) > 0 {
-				// try next largest parcel to attempt to fit whole order in one package
-				continue
-			}
-			break
+	return carrierQuote{carrier: carrier, to: to, shipment: shipment, packages: packages, plan: plan}, nil
+}
 
-		}
+// create address object with customer info
+// an error is returned if the address is not valid
+func createShipmentAddress(rp rateops.AddressCreator, data store.Address) (rateops.Address, error) {
+	addr, err := rp.CreateAddress(data, true)
+	if err != nil {
+		log.Printf("createShipmentAddress failed: %v", err)
+		return rateops.Address{}, err
+	}
+	log.Printf("validation result: %v; %v", addr.Valid, addr.Messages)
+	if !addr.Valid {
+		return rateops.Address{}, fmt.Errorf("INVALID_ADDRESS")
 	}
+	return addr, nil
+}
 
-	// shippo parcel object
-	parcel, err := c.CreateParcel(pi)
+// create address object with business info
+func createReturnAddress(rp rateops.AddressCreator) (rateops.Address, error) {
+	addr, err := rp.CreateAddress(store.ReturnAddress, false)
 	if err != nil {
-		log.Printf("getParcelForVolume failed: %v", err)
-		return &models.Parcel{}, store.Package{}, rem, pack, err
+		log.Printf("createReturnAddress failed: %v", err)
+		return rateops.Address{}, err
 	}
 
-	return parcel, pkg, rem, pack, nil
+	return addr, nil
 }
 
-// fillParcel fills the selected Parcel with the Order's items and
-// returns a list of any remaining items, the parcel's packing list, and an error value.
-func fillParcel(items []PkgItem, parcel *store.Parcel, resv float32) ([]PkgItem, []PkgItem, error) {
-	rem := []PkgItem{}
-	pack := []PkgItem{}
+// create parcel object for each Package in the cheapest packing plan
+// units that ship in their own container are not packed in catalog parcels
+// the plan is verified before any parcel is created; a packops.PackingError fails the order rather than dropping the carrier
+// the exact search budget starts when the order is packed, after the shipping API & DB calls
+func createParcels(rp rateops.ParcelCreator, pl packops.Planner, est packops.RateEstimator, items []*store.CartItem, parcels []*store.Parcel, carrier string) ([]rateops.Parcel, []store.Package, packops.Plan, error) {
+	parcelObjs := []rateops.Parcel{}
+	packages := []store.Package{}
 
-	if len(items) == 0 {
-		log.Println("fill parcel - empty item list")
-		return rem, pack, nil
+	plan, err := packops.PackOrder(pl, est, items, parcels, carrier, time.Now().Add(packops.DefaultExactBudget))
+	if err != nil {
+		log.Printf("createParcels failed: %v", err)
+		return parcelObjs, packages, plan, err
 	}
 
-	d := parcel.ParcelDimensions
-	floats, err := d.GetFloatsMM()
+	// fail impossible packages instead of quoting them
+	err = pl.Verify(items, plan.Parcels)
 	if err != nil {
-		log.Printf("fillParcel failed - get parcel floats: %v", err)
-		return rem, pack, err
+		log.Printf("createParcels failed - verify packing: %v", err)
+		return parcelObjs, packages, packops.Plan{}, err
 	}
-	mL, mW, mH := floats[0], floats[1], floats[2]
 
-	box := &Box{
-		Length:  mL,
-		Width:   mW,
-		Height:  mH,
-		Volume:  mL * mW * mH,
-		ResvPct: resv,
-	}
+	for i, p := range plan.Parcels {
+		parcel, err := rp.CreateParcel(p)
+		if err != nil {
+			log.Printf("createParcels failed: %v", err)
+			return []rateops.Parcel{}, []store.Package{}, packops.Plan{}, err
+		}
+		parcelObjs = append(parcelObjs, parcel)
 
-	// get remaining and packaged items from greedy algorithm
-	rem, pack = addToBox(items, box)
+		// store 3D packing plan with package for fulfillment
+		pkg := p.Package
+		pkg.Placements = packops.Placements(p, i)
+		packages = append(packages, pkg)
+	}
 
-	return rem, pack, nil
+	return parcelObjs, packages, plan, nil
 }
 
-// addToBox recursively adds a list of PkgItems to a Root Box (representing a Parcel object),
-// until there are no remaining items, or there is an insufficient amount of space
-// left in the Root Box for the remaining items.
-// The remaining items and the list of packed items are returned to the caller.
-func addToBox(items []PkgItem, box *Box) ([]PkgItem, []PkgItem) {
-	rem := []PkgItem{}  // remaining items
-	pack := []PkgItem{} // packing list
+// tableEstimator implements the packops.RateEstimator interface with the cheapest rate of the carrier
+// estimated offline by the rate tables for the destination address, so candidate plans are ranked without
+// shipping API calls. The fallback estimator is used if the tables have no rate of the carrier for the plan.
+type tableEstimator struct {
+	tables   []*store.RateTable
+	carrier  string
+	to       store.Address
+	fallback packops.RateEstimator
+}
 
-	if len(items) == 0 {
-		// edge case
-		return rem, pack
+// EstimatePlan returns the cheapest table rate for a shipment containing the plan's parcels.
+func (e *tableEstimator) EstimatePlan(parcels []packops.PackedParcel) (float32, error) {
+	tr := rateops.NewTableRates(e.tables)
+	to, err := tr.CreateAddress(e.to, false)
+	if err != nil {
+		log.Printf("EstimatePlan - table rates unavailable; using fallback: %v", err)
+		return e.fallback.EstimatePlan(parcels)
 	}
-	if box.Length == 0.0 || box.Width == 0.0 || box.Height == 0.0 {
-		// no remaining space along either axis
-		return items, pack
+	parcelObjs := []rateops.Parcel{}
+	for _, p := range parcels {
+		parcel, err := tr.CreateParcel(p)
+		if err != nil {
+			log.Printf("EstimatePlan - table rates unavailable; using fallback: %v", err)
+			return e.fallback.EstimatePlan(parcels)
+		}
+		parcelObjs = append(parcelObjs, parcel)
 	}
 
-	// add current item to current box; create child nodes
-	err := box.Add(items[0])
+	shipment, err := tr.CreateShipment(rateops.Address{}, to, parcelObjs)
 	if err != nil {
-		// next largest does not fit; add to remaining
-		rem = append(rem, items[0])
-		if len(items) > 1 {
-			// fill with remaining items if amy
-			r, p := addToBox(items[1:], box)
-			rem = append(rem, r...)
-			pack = append(pack, p...)
-			return rem, pack
-		} else {
-			// no remaining items
-			return rem, pack
-		}
-	} else {
-		// item fits in box - add item to packing list
-		pack = append(pack, items[0])
-	}
-	if len(items) > 1 {
-		// fill Length branch/Box with remaining items
-		res, pk := addToBox(items[1:], box.NodeL)
-		pack = append(pack, pk...)
-		if len(res) != 0 {
-			// Length branch does not contain sufficient space to fill total remaining items
-			// fill Width branch/Box with remaining items from Length branch
-			res, pk = addToBox(res, box.NodeW)
-			pack = append(pack, pk...)
-		} else {
-			// total of remainder of items packed into Length Branch
-			return rem, pack
-		}
-		if len(res) != 0 {
-			// Width branch does not contain sufficient space to fill remainder of
-			// items from Length Branch
-			// fill Height branch/Box with remaining items from Width branch
-			res, pk = addToBox(res, box.NodeH)
-		} else {
-			// total of remainder of items packed into Width Branch
-			return rem, pack
-		}
-
-		// add any remaining and packed items
-		rem = append(rem, res...)  // remainder of items that could not fit in Height branch (if any)
-		pack = append(pack, pk...) // items packed in Height branch (if any)
+		log.Printf("EstimatePlan - table rates unavailable; using fallback: %v", err)
+		return e.fallback.EstimatePlan(parcels)
 	}
-	return rem, pack
-}
 
-// create store.Shipment object for order fullfillment
-func createShipmentObject(user customerInfo, s *models.Shipment, pkgs []store.Package) store.Shipment {
-	addr := store.Address{
-		FirstName:    s.AddressTo.Name,
-		Company:      s.AddressTo.Company,
-		AddressLine1: s.AddressTo.Street1,
-		AddressLine2: s.AddressTo.Street2,
-		City:         s.AddressTo.City,
-		State:        s.AddressTo.State,
-		Country:      s.AddressTo.Country,
-		Zip:          s.AddressTo.Zip,
-		PhoneNumber:  s.AddressTo.Phone,
-		Email:        s.AddressTo.Email,
-	}
-
-	rates := []store.RateSummary{}
-	for _, rate := range s.Rates {
-		if rate.Provider != "USPS" {
+	cheapest := float32(0.0)
+	found := false
+	for _, rate := range shipment.Rates {
+		if !quotedFor(e.carrier, rate.ServiceLevel.Token) || rate.PriceFloat <= 0 {
 			continue
 		}
-		sl := store.ServiceLevel{
-			Name:  rate.ServiceLevel.Name,
-			Token: rate.ServiceLevel.Token,
-			Terms: rate.ServiceLevel.Terms,
-		}
-		p, _ := strconv.ParseFloat(rate.AmountLocal, 32)
-		rs := store.RateSummary{
-			Price:        rate.AmountLocal,
-			PriceFloat:   float32(p),
-			Currency:     rate.Currency,
-			Provider:     rate.Provider,
-			Days:         rate.Days,
-			ServiceLevel: sl,
+		if !found || rate.PriceFloat < cheapest {
+			cheapest, found = rate.PriceFloat, true
 		}
-		rates = append(rates, rs)
 	}
+	if !found {
+		log.Printf("EstimatePlan - no %s rates; using fallback", e.carrier)
+		return e.fallback.EstimatePlan(parcels)
+	}
+
+	return cheapest, nil
+}
 
+// create store.Shipment object for order fullfillment
+// the rates of each carrier's quote are merged, and each carrier's packing plan is stored in Plans by carrier;
+// the plan of the carrier quoting the cheapest rate is the shipment's plan until the label is purchased
+func createShipmentObject(user customerInfo, quotes []carrierQuote) store.Shipment {
 	shipment := store.Shipment{
 		UserID:      user.UserID,
 		OrderID:     user.OrderID,
-		Status:      s.Status,
-		AddressTo:   addr,
+		Status:      quotes[0].shipment.Status,
+		AddressTo:   quotes[0].to.Address,
 		AddressFrom: store.ReturnAddress,
-		Packages:    pkgs,
-		Rates:       rates,
+		Rates:       []store.RateSummary{},
+		Plans:       make(map[string]store.PackingPlan),
+	}
+
+	cheapest := quotes[0].carrier
+	found := false
+	low := float32(0.0)
+	for _, q := range quotes {
+		shipment.Plans[q.carrier] = store.PackingPlan{
+			Packages:     q.packages,
+			PackingTrace: packops.Trace(q.plan.Parcels),
+			PlanReason:   q.plan.Reason,
+		}
+
+		for _, rate := range q.shipment.Rates {
+			if !quotedFor(q.carrier, rate.ServiceLevel.Token) {
+				continue
+			}
+			shipment.Rates = append(shipment.Rates, rate.Summary(q.carrier))
+			if !found || rate.PriceFloat < low {
+				cheapest, low, found = q.carrier, rate.PriceFloat, true
+			}
+		}
 	}
 
+	plan := shipment.Plans[cheapest]
+	shipment.Packages, shipment.PackingTrace, shipment.PlanReason = plan.Packages, plan.PackingTrace, plan.PlanReason
+
 	return shipment
 }
 
@@ -1,14 +1,19 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/ggarcia209/acamoprjct/service/store-api/store"
 	"github.com/ggarcia209/acamoprjct/service/util/dbops"
+	"github.com/ggarcia209/acamoprjct/service/util/packops"
+	"github.com/ggarcia209/acamoprjct/service/util/rateops"
 	"github.com/ggarcia209/acamoprjct/service/util/shipops"
-	"github.com/ggarcia209/acamoprjct/service/util/sortops"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
 )
 
 func getTestToken() (string, error) {
@@ -26,757 +31,66 @@ func TestCreateReturnAddress(t *testing.T) {
 		t.Errorf("FAIL - get token: %v", err)
 	}
 
-	client := shipops.InitClient(token)
-	addr, err := createReturnAddress(client)
+	rp := rateops.NewShippo(shipops.InitClient(token))
+	addr, err := createReturnAddress(rp)
 	if err != nil {
 		t.Errorf("FAIL: %v", err)
 	}
 	t.Logf("%v", addr)
 }
 
-func TestAddToBox(t *testing.T) {
-	var tests = []struct {
-		items   []PkgItem
-		box     *Box
-		wantIds []string
-		wantErr error
-	}{
-		{
-			items: []PkgItem{
-				PkgItem{ItemID: "001", Name: "Item 1", Length: 8.0, Width: 8.0, Height: 3.0, Volume: 192.0},
-				PkgItem{ItemID: "002", Name: "Item 2", Length: 5.0, Width: 5.0, Height: 2.0, Volume: 50.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-			},
-			box: &Box{
-				Length:  12.0,
-				Width:   12.0,
-				Height:  6.0,
-				Volume:  864.0,
-				ResvPct: 0.00,
-			},
-			wantIds: []string{"001", "002", "003"},
-			wantErr: nil,
-		},
-		{
-			items: []PkgItem{
-				PkgItem{ItemID: "001", Name: "Item 1", Length: 8.0, Width: 8.0, Height: 3.0, Volume: 192.0},
-				PkgItem{ItemID: "002", Name: "Item 2", Length: 5.0, Width: 5.0, Height: 2.0, Volume: 50.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-			},
-			box: &Box{
-				Length:  12.0,
-				Width:   12.0,
-				Height:  6.0,
-				Volume:  864.0,
-				ResvPct: 0.20,
-			},
-			wantIds: []string{"001", "002", "003", "003", "003", "003", "003", "003"},
-			wantErr: nil,
-		},
-		{
-			items: []PkgItem{
-				PkgItem{ItemID: "001", Name: "Item 1", Length: 8.0, Width: 8.0, Height: 3.0, Volume: 192.0},
-				PkgItem{ItemID: "002", Name: "Item 2", Length: 5.0, Width: 5.0, Height: 2.0, Volume: 50.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-			},
-			box: &Box{
-				Length:  6.0,
-				Width:   6.0,
-				Height:  6.0,
-				Volume:  864.0,
-				ResvPct: 0.20,
-			},
-			wantIds: []string{"002", "003", "003", "003", "003"},
-			wantErr: nil,
-		},
-		{
-			items: []PkgItem{
-				PkgItem{ItemID: "001", Name: "Item 1", Length: 8.0, Width: 8.0, Height: 2.0, Volume: 192.0},
-				PkgItem{ItemID: "002", Name: "Item 2", Length: 5.0, Width: 5.0, Height: 2.0, Volume: 50.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-			},
-			box: &Box{
-				Length:  6.0,
-				Width:   6.0,
-				Height:  6.0,
-				Volume:  216.0,
-				ResvPct: 0.00,
-			},
-			wantIds: []string{"002", "003", "003", "003", "003"},
-			wantErr: nil,
-		},
-		{
-			items: []PkgItem{
-				PkgItem{ItemID: "002", Name: "Item 2", Length: 6.0, Width: 6.0, Height: 2.0, Volume: 72.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-			},
-			box: &Box{
-				Length:  6.0,
-				Width:   6.0,
-				Height:  6.0,
-				Volume:  216.0,
-				ResvPct: 0.00,
-			},
-			wantIds: []string{"002", "003", "003", "003", "003", "003", "003", "003", "003"},
-			wantErr: nil,
-		},
-		{
-			items: []PkgItem{
-				PkgItem{ItemID: "002", Name: "Item 2", Length: 8.0, Width: 8.0, Height: 3.0, Volume: 192.0},
-			},
-			box: &Box{
-				Length:  6.0,
-				Width:   6.0,
-				Height:  6.0,
-				Volume:  216.0,
-				ResvPct: 0.00,
-			},
-			wantIds: []string{},
-			wantErr: nil,
-		},
-		{
-			items: []PkgItem{
-				PkgItem{ItemID: "002", Name: "Item 2", Length: 6.0, Width: 6.0, Height: 2.0, Volume: 72.0},
-			},
-			box: &Box{
-				Length:  6.0,
-				Width:   6.0,
-				Height:  6.0,
-				Volume:  216.0,
-				ResvPct: 0.00,
-			},
-			wantIds: []string{"002"},
-			wantErr: nil,
-		},
-		{
-			items: []PkgItem{},
-			box: &Box{
-				Length:  6.0,
-				Width:   6.0,
-				Height:  6.0,
-				Volume:  216.0,
-				ResvPct: 0.00,
-			},
-			wantIds: []string{},
-			wantErr: nil,
-		},
-	}
-
-	for _, test := range tests {
-		t.Logf("*** TEST ****")
-		rem, pack := addToBox(test.items, test.box)
-		t.Logf("rem: %v", rem)
-		t.Logf("pack: %v", pack)
-		t.Log("")
-		t.Log("")
-		if len(pack) != len(test.wantIds) {
-			t.Errorf("FAIL - data: %d; want: %d", len(pack), len(test.wantIds))
-		}
-		for i, item := range pack {
-			if item.ItemID != test.wantIds[i] {
-				t.Errorf("FAIL - data: %s; want: %s", item.ItemID, test.wantIds[i])
-			}
-		}
-	}
-}
-
-func TestFillParcel(t *testing.T) {
-	var tests = []struct {
-		parcel  *store.Parcel
-		items   []PkgItem
-		resv    float32
-		wantIds []string
-		wantErr error
-	}{
-		{
-			items: []PkgItem{
-				PkgItem{ItemID: "001", Name: "Item 1", Length: 8.0, Width: 8.0, Height: 3.0, Volume: 192.0},
-				PkgItem{ItemID: "002", Name: "Item 2", Length: 5.0, Width: 5.0, Height: 2.0, Volume: 50.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-			},
-			parcel: &store.Parcel{
-				Carrier:  "usps",
-				ParcelID: "usps_largeflatratebox",
-				Name:     "USPS Large Flat Rage Box",
-				ParcelDimensions: store.Dimensions{
-					Length: "12.0",
-					Width:  "12.0",
-					Height: "6.0",
-					Weight: "1.0",
-					Volume: 864.0,
-				},
-			},
-			resv:    0.2,
-			wantIds: []string{"001", "002", "003"},
-			wantErr: nil,
-		},
-		{
-			items: []PkgItem{
-				PkgItem{ItemID: "001", Name: "Item 1", Length: 8.0, Width: 8.0, Height: 3.0, Volume: 192.0},
-				PkgItem{ItemID: "002", Name: "Item 2", Length: 5.0, Width: 5.0, Height: 2.0, Volume: 50.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-			},
-			parcel: &store.Parcel{
-				Carrier:  "usps",
-				ParcelID: "usps_largeflatratebox",
-				Name:     "USPS Large Flat Rage Box",
-				ParcelDimensions: store.Dimensions{
-					Length: "12.0",
-					Width:  "12.0",
-					Height: "6.0",
-					Weight: "1.0",
-					Volume: 864.0,
-				},
-			},
-			resv:    0.2,
-			wantIds: []string{"001", "002", "003", "003", "003", "003", "003", "003"},
-			wantErr: nil,
-		},
-		{
-			items: []PkgItem{
-				PkgItem{ItemID: "001", Name: "Item 1", Length: 8.0, Width: 8.0, Height: 3.0, Volume: 192.0},
-				PkgItem{ItemID: "002", Name: "Item 2", Length: 5.0, Width: 5.0, Height: 2.0, Volume: 50.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-			},
-			parcel: &store.Parcel{
-				Carrier:  "usps",
-				ParcelID: "usps_squarebox",
-				Name:     "USPS Square Box",
-				ParcelDimensions: store.Dimensions{
-					Length: "6.0",
-					Width:  "6.0",
-					Height: "6.0",
-					Weight: "1.0",
-					Volume: 216.0,
-				},
-			},
-			resv:    0.2,
-			wantIds: []string{"002", "003", "003", "003", "003"},
-			wantErr: nil,
-		},
-		{
-			items: []PkgItem{
-				PkgItem{ItemID: "001", Name: "Item 1", Length: 8.0, Width: 8.0, Height: 2.0, Volume: 192.0},
-				PkgItem{ItemID: "002", Name: "Item 2", Length: 5.0, Width: 5.0, Height: 2.0, Volume: 50.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-			},
-			parcel: &store.Parcel{
-				Carrier:  "usps",
-				ParcelID: "usps_squarebox",
-				Name:     "USPS Square Box",
-				ParcelDimensions: store.Dimensions{
-					Length: "6.0",
-					Width:  "6.0",
-					Height: "6.0",
-					Weight: "1.0",
-					Volume: 216.0,
-				},
-			},
-			resv:    0.0,
-			wantIds: []string{"002", "003", "003", "003", "003"},
-			wantErr: nil,
-		},
-		{
-			items: []PkgItem{
-				PkgItem{ItemID: "002", Name: "Item 2", Length: 6.0, Width: 6.0, Height: 2.0, Volume: 72.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
-			},
-			parcel: &store.Parcel{
-				Carrier:  "usps",
-				ParcelID: "usps_squarebox",
-				Name:     "USPS Square Box",
-				ParcelDimensions: store.Dimensions{
-					Length: "6.0",
-					Width:  "6.0",
-					Height: "6.0",
-					Weight: "1.0",
-					Volume: 216.0,
-				},
-			},
-			resv:    0.0,
-			wantIds: []string{"002", "003", "003", "003", "003", "003", "003", "003", "003"},
-			wantErr: nil,
-		},
-		{
-			items: []PkgItem{
-				PkgItem{ItemID: "002", Name: "Item 2", Length: 8.0, Width: 8.0, Height: 3.0, Volume: 192.0},
-			},
-			parcel: &store.Parcel{
-				Carrier:  "usps",
-				ParcelID: "usps_squarebox",
-				Name:     "USPS Square Box",
-				ParcelDimensions: store.Dimensions{
-					Length: "6.0",
-					Width:  "6.0",
-					Height: "6.0",
-					Weight: "1.0",
-					Volume: 216.0,
-				},
-			},
-			resv:    0.0,
-			wantIds: []string{},
-			wantErr: nil,
-		},
-		{ // edge case - single item fills 100% of volume
-			items: []PkgItem{
-				PkgItem{ItemID: "002", Name: "Item 2", Length: 6.0, Width: 6.0, Height: 2.0, Volume: 72.0},
-			},
-			parcel: &store.Parcel{
-				Carrier:  "usps",
-				ParcelID: "usps_squarebox",
-				Name:     "USPS Square Box",
-				ParcelDimensions: store.Dimensions{
-					Length: "6.0",
-					Width:  "6.0",
-					Height: "6.0",
-					Weight: "1.0",
-					Volume: 216.0,
-				},
-			},
-			resv:    0.0,
-			wantIds: []string{"002"},
-			wantErr: nil,
-		},
-		{ // edge case - empty list
-			items: []PkgItem{},
-			parcel: &store.Parcel{
-				Carrier:  "usps",
-				ParcelID: "usps_squarebox",
-				Name:     "USPS Square Box",
-				ParcelDimensions: store.Dimensions{
-					Length: "6.0",
-					Width:  "6.0",
-					Height: "6.0",
-					Weight: "1.0",
-					Volume: 216.0,
-				},
-			},
-			resv:    0.0,
-			wantIds: []string{},
-			wantErr: nil,
-		},
-	}
-	for _, test := range tests {
-		rem, pack, err := fillParcel(test.items, test.parcel, test.resv)
-		if err != test.wantErr {
-			t.Errorf("FAIL: %v; want: %v", err, test.wantErr)
-		}
-		if len(pack) != len(test.wantIds) {
-			t.Errorf("FAIL - data: %d; want: %d", len(pack), len(test.wantIds))
-			t.Logf("items: %v", test.items)
-			t.Logf("rem: %v", rem)
-			t.Logf("pack: %v", pack)
-			return
-		}
-		for i, item := range pack {
-			if item.ItemID != test.wantIds[i] {
-				t.Errorf("FAIL - data: %s; want: %s", item.ItemID, test.wantIds[i])
-			}
-		}
-		t.Logf("rem: %v", rem)
-		t.Logf("pack: %v", pack)
-	}
-}
-
-func TestGetDimensions(t *testing.T) {
-	var tests = []struct {
-		items  []*store.CartItem
-		length float32
-		width  float32
-		height float32
-		weight float32
-		volume float32
-	}{
-		{
-			items: []*store.CartItem{
-				&store.CartItem{
-					ItemID:             "001",
-					SizeID:             "001-OS",
-					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "8.0", Width: "8.0", Height: "3.0", Weight: "1.0", Volume: 192.00},
-				},
-				&store.CartItem{
-					ItemID:             "002",
-					SizeID:             "002-OS",
-					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "5.0", Width: "5.0", Height: "2.0", Weight: "0.5", Volume: 50.00},
-				},
-				&store.CartItem{
-					ItemID:             "001",
-					SizeID:             "001-OS",
-					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", Weight: "0.5", Volume: 18.00},
-				},
-			},
-			length: 8.0,
-			width:  8.0,
-			height: 3.0,
-			weight: 2.0,
-			volume: 260,
-		},
-		{
-			items: []*store.CartItem{
-				&store.CartItem{
-					ItemID:             "001",
-					SizeID:             "001-OS",
-					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "8.0", Width: "8.0", Height: "3.0", Weight: "1.0", Volume: 192.00},
-				},
-				&store.CartItem{
-					ItemID:             "002",
-					SizeID:             "002-OS",
-					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "5.0", Width: "5.0", Height: "2.0", Weight: "0.5", Volume: 50.00},
-				},
-				&store.CartItem{
-					ItemID:             "001",
-					SizeID:             "001-OS",
-					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", Weight: "0.5", Volume: 18.00},
-				},
-				&store.CartItem{
-					ItemID:             "001",
-					SizeID:             "001-OS",
-					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", Weight: "0.5", Volume: 18.00},
-				},
-				&store.CartItem{
-					ItemID:             "001",
-					SizeID:             "001-OS",
-					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", Weight: "0.5", Volume: 18.00},
-				},
-				&store.CartItem{
-					ItemID:             "001",
-					SizeID:             "001-OS",
-					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", Weight: "0.5", Volume: 18.00},
-				},
-			},
-			length: 8.0,
-			width:  8.0,
-			height: 3.0,
-			weight: 3.5,
-			volume: 314,
-		},
-		{
-			items: []*store.CartItem{
-				&store.CartItem{
-					ItemID:             "002",
-					SizeID:             "002-OS",
-					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "5.0", Width: "5.0", Height: "2.0", Weight: "0.5", Volume: 50.00},
-				},
-			},
-			length: 5.0,
-			width:  5.0,
-			height: 2.0,
-			weight: 0.5,
-			volume: 50.0,
-		},
-		{
-			items:  []*store.CartItem{},
-			length: 0.0,
-			width:  0.0,
-			height: 0.0,
-			weight: 0.0,
-			volume: 0.0,
-		},
-	}
-	for _, test := range tests {
-		d, err := getDimensions(test.items)
-		if err != nil {
-			t.Errorf("FAIL: %v", err)
-			return
-		}
-		if d.MaxLength != test.length {
-			t.Errorf("FAIL - length: %f; want: %f", d.MaxLength, test.length)
-		}
-		if d.MaxWidth != test.width {
-			t.Errorf("FAIL - width: %f; want: %f", d.MaxWidth, test.width)
-		}
-		if d.MaxHeight != test.height {
-			t.Errorf("FAIL - height: %f; want: %f", d.MaxHeight, test.height)
-		}
-		if d.Weight != test.weight {
-			t.Errorf("FAIL - weight: %f; want: %f", d.Weight, test.weight)
-		}
-		if d.Volume != test.volume {
-			t.Errorf("FAIL - volume: %f; want: %f", d.Volume, test.volume)
-		}
-	}
-}
-
-func TestGetParcelForVolume(t *testing.T) {
+func TestCreateParcels(t *testing.T) {
 	var tests = []struct {
 		items   []*store.CartItem
-		resv    float32
 		wantErr error
 	}{
-		{
-			items: []*store.CartItem{
-				&store.CartItem{
-					ItemID:             "001",
-					SizeID:             "001-OS",
-					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "8.0", Width: "8.0", Height: "3.0", Weight: "1.0", Volume: 192.00},
-				},
-				&store.CartItem{
-					ItemID:             "002",
-					SizeID:             "002-OS",
-					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "5.0", Width: "5.0", Height: "2.0", Weight: "0.5", Volume: 50.00},
-				},
-				&store.CartItem{
-					ItemID:             "003",
-					SizeID:             "003-OS",
-					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", Weight: "0.5", Volume: 18.00},
-				},
-			},
-			resv:    0.0,
-			wantErr: nil,
-		},
-		{
-			items: []*store.CartItem{
-				&store.CartItem{
-					ItemID:             "001",
-					SizeID:             "001-OS",
-					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "8.0", Width: "8.0", Height: "3.0", Weight: "1.0", Volume: 192.00},
-				},
-				&store.CartItem{
-					ItemID:             "002",
-					SizeID:             "002-OS",
-					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "5.0", Width: "5.0", Height: "2.0", Weight: "0.5", Volume: 50.00},
-				},
-				&store.CartItem{
-					ItemID:             "003",
-					SizeID:             "003-OS",
-					Quantity:           4,
-					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", Weight: "0.5", Volume: 18.00},
-				},
-			},
-			resv:    0.2,
-			wantErr: nil,
-		},
-		{
+		{ // 1 square box
 			items: []*store.CartItem{
-				&store.CartItem{
-					ItemID:             "001",
-					SizeID:             "001-OS",
-					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "8.0", Width: "8.0", Height: "3.0", Weight: "1.0", Volume: 192.00},
-				},
 				&store.CartItem{
 					ItemID:             "002",
 					SizeID:             "002-OS",
 					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "5.0", Width: "5.0", Height: "2.0", Weight: "0.5", Volume: 50.00},
+					ShippingDimensions: store.Dimensions{Length: "5.0", Width: "5.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 50.00},
 				},
 				&store.CartItem{
 					ItemID:             "003",
 					SizeID:             "003-OS",
-					Quantity:           4,
-					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", Weight: "0.5", Volume: 18.00},
-				},
-			},
-			resv:    0.0,
-			wantErr: nil,
-		},
-		{
-			items: []*store.CartItem{
-				&store.CartItem{
-					ItemID:             "002",
-					SizeID:             "002-OS",
 					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "6.0", Width: "6.0", Height: "2.0", Weight: "0.5", Volume: 50.00},
-				},
-				&store.CartItem{
-					ItemID:             "003",
-					SizeID:             "003-OS",
-					Quantity:           8,
-					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", Weight: "0.5", Volume: 18.00},
+					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
 				},
 			},
-			resv:    0.0,
 			wantErr: nil,
 		},
-		{
+		{ // 1 square box
 			items: []*store.CartItem{
 				&store.CartItem{
-					ItemID:             "002",
-					SizeID:             "002-OS",
+					ItemID:             "004",
+					SizeID:             "004-13x13",
 					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "6.0", Width: "6.0", Height: "2.0", Weight: "0.5", Volume: 50.00},
-				},
-				&store.CartItem{
-					ItemID:             "003",
-					SizeID:             "003-OS",
-					Quantity:           8,
-					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", Weight: "0.5", Volume: 18.00},
-				},
-			},
-			resv:    0.2,
-			wantErr: nil,
-		},
-		{ // 1 large flate rate + 1 square
-			items: []*store.CartItem{
-				&store.CartItem{
-					ItemID:             "002",
-					SizeID:             "002-OS",
-					Quantity:           4,
-					ShippingDimensions: store.Dimensions{Length: "5.0", Width: "5.0", Height: "5.0", Weight: "0.5", Volume: 125.00},
-				},
-				&store.CartItem{
-					ItemID:             "003",
-					SizeID:             "003-OS",
-					Quantity:           10,
-					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", Weight: "0.5", Volume: 18.00},
+					ShippingDimensions: store.Dimensions{Length: "5", Width: "5", Height: "5", DistanceUnit: "in", Weight: "1", MassUnit: "lb", Volume: 125.00, VolumeUnit: "in"},
 				},
 			},
-			resv:    0.0,
 			wantErr: nil,
 		},
-		{
+		{ // 1 square box
 			items: []*store.CartItem{
 				&store.CartItem{
-					ItemID:             "002",
-					SizeID:             "002-OS",
+					ItemID:             "004",
+					SizeID:             "004-13x13",
 					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "5.0", Width: "5.0", Height: "2.0", Weight: "0.5", Volume: 50.00},
+					ShippingDimensions: store.Dimensions{Length: "3", Width: "3", Height: "3", DistanceUnit: "in", Weight: "1", MassUnit: "lb", Volume: 27.00, VolumeUnit: "in"},
 				},
 			},
-			resv:    0.0,
-			wantErr: nil,
-		},
-		{
-			items:   []*store.CartItem{},
-			resv:    0.0,
 			wantErr: nil,
 		},
-	}
-
-	token, err := getTestToken()
-	if err != nil {
-		t.Errorf("FAIL - get token: %v", err)
-	}
-	client := shipops.InitClient(token)
-
-	os.Setenv(dbops.EnvarParcelsTable, "acamoprjct-parcels-dev")
-	os.Setenv(dbops.EnvarStoreItemsIndexTable, "acamoprjct-store-items-index-dev")
-	table1 := dbops.NewTable(dbops.ParcelsTable(), dbops.ParcelsPK, "")
-	table2 := dbops.NewTable(dbops.StoreItemsIndexTable(), dbops.StoreItemsIndexPK, "")
-	tables := []dbops.Table{table1, table2}
-	dbInfo := dbops.InitDB(tables)
-
-	indexKey := "parcels-usps"
-	index, err := dbops.GetStoreItemIndex(dbInfo, indexKey)
-	if err != nil {
-		t.Errorf("FAIL - index: %v", err)
-		return
-	}
-	t.Logf("index: %v", index.ItemIDs)
-	parcels, err := dbops.BatchGetParcels(dbInfo, index.ItemIDs)
-	if err != nil {
-		t.Errorf("FAIL - get parcels: %v", err)
-		return
-	}
-	t.Logf("%v", parcels)
-
-	for _, test := range tests {
-		// split packages with greedy algorithm if total order volume is greater than largest parcel volume
-		sortedByVol := sortops.SortCartItemsByUnitVolume(test.items)
-		// create package item for each individual unit in order
-		pkgItems := []PkgItem{}
-		for _, item := range sortedByVol {
-			for j := 0; j < item.Quantity; j++ {
-				pd := item.ShippingDimensions
-				floats, err := pd.GetFloats()
-				if err != nil {
-					t.Errorf("FAIL - get parcels: %v", err)
-					return
-				}
-				pi := PkgItem{
-					ItemID: item.SizeID,
-					Name:   item.Name,
-					Length: floats[0],
-					Width:  floats[1],
-					Height: floats[2],
-					Volume: floats[0] * floats[1] * floats[2],
-				}
-				pkgItems = append(pkgItems, pi)
-			}
-		}
-		parcel, pkg, rem, pack, err := getParcelForVolume(client, parcels, sortedByVol, pkgItems, test.resv)
-		if err != test.wantErr {
-			t.Errorf("FAIL: %v; want: %v", err, test.wantErr)
-			return
-		}
-		t.Logf("parcel: %v", parcel)
-		t.Logf("package: %v", pkg)
-		t.Logf("rem: %v", rem)
-		t.Logf("pack: %v", pack)
-	}
-}
-
-func TestCreateParcels(t *testing.T) {
-	var tests = []struct {
-		items   []*store.CartItem
-		wantErr error
-	}{
-		{ // 1 square box
+		{ // own container + 1 square box
 			items: []*store.CartItem{
 				&store.CartItem{
-					ItemID:             "002",
-					SizeID:             "002-OS",
+					ItemID:             "pawnwars",
+					SizeID:             "pawnwars-retail",
 					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "5.0", Width: "5.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 50.00},
+					ShippingDimensions: store.Dimensions{Length: "12.0", Width: "12.0", Height: "3.0", DistanceUnit: "in", Weight: "2.5", MassUnit: "lb", Volume: 432.00},
+					Handling:           store.Handling{OwnContainer: true},
 				},
 				&store.CartItem{
 					ItemID:             "003",
@@ -786,28 +100,6 @@ func TestCreateParcels(t *testing.T) {
 				},
 			},
 			wantErr: nil,
-		},
-		{ // 1 square box
-			items: []*store.CartItem{
-				&store.CartItem{
-					ItemID:             "004",
-					SizeID:             "004-13x13",
-					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "5", Width: "5", Height: "5", DistanceUnit: "in", Weight: "1", MassUnit: "lb", Volume: 125.00, VolumeUnit: "in"},
-				},
-			},
-			wantErr: nil,
-		},
-		{ // 1 square box
-			items: []*store.CartItem{
-				&store.CartItem{
-					ItemID:             "004",
-					SizeID:             "004-13x13",
-					Quantity:           1,
-					ShippingDimensions: store.Dimensions{Length: "3", Width: "3", Height: "3", DistanceUnit: "in", Weight: "1", MassUnit: "lb", Volume: 27.00, VolumeUnit: "in"},
-				},
-			},
-			wantErr: nil,
 		}, /*
 			{ // 1 large flat rate
 				items: []*store.CartItem{
@@ -893,7 +185,7 @@ func TestCreateParcels(t *testing.T) {
 	if err != nil {
 		t.Errorf("FAIL - get token: %v", err)
 	}
-	client := shipops.InitClient(token)
+	rp := rateops.NewShippo(shipops.InitClient(token))
 
 	os.Setenv(dbops.EnvarParcelsTable, "acamoprjct-parcels-dev")
 	os.Setenv(dbops.EnvarStoreItemsIndexTable, "acamoprjct-store-items-index-dev")
@@ -918,7 +210,7 @@ func TestCreateParcels(t *testing.T) {
 
 	for _, test := range tests {
 		t.Log("*** TEST ***")
-		parcels, packages, err := createParcels(client, test.items, parcels)
+		parcels, packages, plan, err := createParcels(rp, packops.NewPlanner(packops.NewPacker()), packops.DefaultTableRates, test.items, parcels, store.CarriersUsps)
 		if err != test.wantErr {
 			t.Errorf("FAIL: %v; want: %v", err, test.wantErr)
 		}
@@ -928,6 +220,10 @@ func TestCreateParcels(t *testing.T) {
 		for _, p := range packages {
 			t.Logf("package: %v", p)
 		}
+		t.Logf("plan: %s", plan.Reason)
+		for _, line := range packops.Instructions(packages) {
+			t.Logf("packing: %s", line)
+		}
 		t.Log("----------")
 		t.Log("")
 	}
@@ -951,7 +247,7 @@ func TestGetShippingRates(t *testing.T) {
 	if err != nil {
 		t.Errorf("FAIL - get token: %v", err)
 	}
-	client := shipops.InitClient(token)
+	rp := rateops.NewShippo(shipops.InitClient(token))
 
 	os.Setenv(dbops.EnvarParcelsTable, "acamoprjct-parcels-dev")
 	os.Setenv(dbops.EnvarStoreItemsIndexTable, "acamoprjct-store-items-index-dev")
@@ -975,7 +271,7 @@ func TestGetShippingRates(t *testing.T) {
 
 		t.Logf("parcels table: %v", dbInfo.Tables[dbops.ParcelsTable()])
 
-		rates, shipment, err := getShippingRates(dbInfo, client, test.info, order)
+		rates, shipment, err := getShippingRates(dbInfo, rp, packops.NewPlanner(packops.NewPacker()), test.info, order)
 		if err != test.wantErr {
 			t.Errorf("FAIL: %v; want: %v", err, test.wantErr)
 			continue
@@ -984,3 +280,189 @@ func TestGetShippingRates(t *testing.T) {
 		t.Logf("shipment: %v", shipment)
 	}
 }
+
+func TestEnabledCarriers(t *testing.T) {
+	var tests = []struct {
+		list string
+		want []string
+	}{
+		{list: "", want: []string{store.CarriersUsps}},
+		{list: "usps,ups", want: []string{"usps", "ups"}},
+		{list: " UPS , fedex,,ups,dhl_express ", want: []string{"ups", "fedex", "dhl_express"}},
+	}
+	for _, test := range tests {
+		got := enabledCarriers(test.list)
+		if strings.Join(got, ",") != strings.Join(test.want, ",") {
+			t.Errorf("FAIL - %q: %v; want: %v", test.list, got, test.want)
+		}
+	}
+}
+
+func TestQuotedFor(t *testing.T) {
+	var tests = []struct {
+		carrier string
+		token   string
+		want    bool
+	}{
+		{carrier: "usps", token: "usps_priority", want: true},
+		{carrier: "ups", token: "usps_priority", want: false},
+		{carrier: "ups", token: "ups_ground", want: true},
+		{carrier: "dhl_express", token: "dhl_express_worldwide", want: true},
+		{carrier: "fedex", token: "fedex_ground", want: true},
+		{carrier: "fedex", token: "", want: false},
+	}
+	for _, test := range tests {
+		if got := quotedFor(test.carrier, test.token); got != test.want {
+			t.Errorf("FAIL - %s %s: %v; want: %v", test.carrier, test.token, got, test.want)
+		}
+	}
+}
+
+func TestCreateShipmentObject(t *testing.T) {
+	quote := func(carrier string, price float32, tokens ...string) carrierQuote {
+		rates := []rateops.Rate{}
+		for _, token := range tokens {
+			rates = append(rates, rateops.Rate{ServiceLevel: store.ServiceLevel{Token: token}, PriceFloat: price})
+			price += 5.0
+		}
+		pkg := store.Package{Carrier: carrier, ParcelID: carrier + "_box", Placements: []store.Placement{store.Placement{ParcelIndex: 0}}}
+		return carrierQuote{
+			carrier:  carrier,
+			to:       rateops.Address{Address: store.Address{FirstName: "Test"}},
+			shipment: rateops.Shipment{Status: "SUCCESS", Rates: rates},
+			packages: []store.Package{pkg},
+			plan:     packops.Plan{Reason: carrier + " greedy"},
+		}
+	}
+	quotes := []carrierQuote{
+		quote("usps", 9.0, "usps_priority", "ups_ground"), // other carrier's rates are dropped
+		quote("ups", 8.0, "ups_ground", "ups_next_day_air"),
+	}
+
+	s := createShipmentObject(customerInfo{UserID: "user", OrderID: "order"}, quotes)
+	if len(s.Rates) != 3 || s.Rates[0].Carrier != "usps" || s.Rates[1].Carrier != "ups" {
+		t.Errorf("FAIL - rates: %v", s.Rates)
+	}
+	// each carrier's plan is indexed from 0 and stored by carrier
+	for _, carrier := range []string{"usps", "ups"} {
+		plan, ok := s.Plans[carrier]
+		if !ok || len(plan.Packages) != 1 || plan.Packages[0].Carrier != carrier || plan.Packages[0].Placements[0].ParcelIndex != 0 || plan.PlanReason != carrier+" greedy" {
+			t.Errorf("FAIL - %s plan: %+v", carrier, plan)
+		}
+	}
+	// cheapest rate's carrier plan is the shipment's plan
+	if len(s.Packages) != 1 || s.Packages[0].Carrier != "ups" || s.PlanReason != "ups greedy" {
+		t.Errorf("FAIL - packages: %v; reason: %s", s.Packages, s.PlanReason)
+	}
+	if s.AddressTo.FirstName != "Test" {
+		t.Errorf("FAIL - shipment: %v", s)
+	}
+}
+
+func TestNewRateProvider(t *testing.T) {
+	os.Setenv(envarShippingAPI, "bogus")
+	defer os.Unsetenv(envarShippingAPI)
+
+	if _, err := newRateProvider(); err == nil || err.Error() != "INVALID_SHIPPING_API" {
+		t.Errorf("FAIL: %v; want: INVALID_SHIPPING_API", err)
+	}
+}
+
+func TestTableEstimator(t *testing.T) {
+	tables := []*store.RateTable{
+		&store.RateTable{Carrier: "usps", Provider: "USPS", ServiceLevel: store.ServiceLevel{Token: "usps_priority"},
+			Tiers: []store.RateTier{store.RateTier{MaxWeightLb: 5.0, Price: 9.0}}},
+		&store.RateTable{Carrier: "usps", Provider: "USPS", ServiceLevel: store.ServiceLevel{Token: "usps_ground_advantage"},
+			Tiers: []store.RateTier{store.RateTier{MaxWeightLb: 2.0, Price: 6.0}}},
+		&store.RateTable{Carrier: "ups", Provider: "UPS", ServiceLevel: store.ServiceLevel{Token: "ups_ground"},
+			Tiers: []store.RateTier{store.RateTier{MaxWeightLb: 5.0, Price: 4.0}}},
+	}
+	fallback := &packops.TableRateEstimator{Rates: map[string][]packops.TableRate{"usps": []packops.TableRate{packops.TableRate{MaxWeightLb: 70.0, Price: 50.0}}}}
+	var tests = []struct {
+		tables  []*store.RateTable
+		weights []float32 // lbs of each parcel
+		want    float32
+	}{
+		{tables: tables, weights: []float32{1.0}, want: 6.0},       // cheapest usps rate; ups rate ignored
+		{tables: tables, weights: []float32{1.0, 3.0}, want: 18.0}, // ground advantage has no tier for 3 lbs
+		{tables: tables, weights: []float32{10.0}, want: 50.0},     // no table rate; fallback
+		{tables: nil, weights: []float32{1.0}, want: 50.0},         // no tables; fallback
+	}
+	for _, test := range tests {
+		est := &tableEstimator{tables: test.tables, carrier: "usps", to: store.Address{Zip: "90001"}, fallback: fallback}
+		parcels := []packops.PackedParcel{}
+		for _, w := range test.weights {
+			parcels = append(parcels, packops.PackedParcel{Package: store.Package{Carrier: "usps"}, Weight: units.Mass(w) * units.Pound})
+		}
+		got, err := est.EstimatePlan(parcels)
+		if err != nil || got != test.want {
+			t.Errorf("FAIL - %v: %v; %v; want: %v", test.weights, got, err, test.want)
+		}
+	}
+}
+
+func TestEstimable(t *testing.T) {
+	var tests = []struct {
+		err  error
+		want bool
+	}{
+		{err: fmt.Errorf("SHIPPO_REQUEST_FAILED"), want: true},
+		{err: fmt.Errorf("INVALID_ADDRESS"), want: false},
+		{err: &packops.PackingError{Code: "PLACEMENTS_OVERLAP"}, want: false},
+		{err: fmt.Errorf("quote usps: %w", &packops.PackingError{Code: "UNITS_MISMATCH"}), want: false},
+	}
+	for _, test := range tests {
+		if got := estimable(test.err); got != test.want {
+			t.Errorf("FAIL - %v: %v; want: %v", test.err, got, test.want)
+		}
+	}
+}
+
+// parcelRecorder implements the rateops.ParcelCreator interface offline
+type parcelRecorder struct {
+	parcels []packops.PackedParcel
+}
+
+func (p *parcelRecorder) CreateParcel(pp packops.PackedParcel) (rateops.Parcel, error) {
+	p.parcels = append(p.parcels, pp)
+	return rateops.Parcel{ID: pp.Package.ParcelID}, nil
+}
+
+func TestCreateParcelsExactSearch(t *testing.T) {
+	// the items only fit the small parcel in the exact search's packing
+	parcels := []*store.Parcel{
+		&store.Parcel{Carrier: store.CarriersUsps, ParcelID: "small", ParcelDimensions: store.Dimensions{Length: "8", Width: "6", Height: "4", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 192}},
+		&store.Parcel{Carrier: store.CarriersUsps, ParcelID: "large", ParcelDimensions: store.Dimensions{Length: "12", Width: "12", Height: "8", DistanceUnit: "in", Weight: "1", MassUnit: "lb", Volume: 1152}},
+	}
+	items := []*store.CartItem{
+		&store.CartItem{ItemID: "001", SizeID: "001-OS", Quantity: 1, ShippingDimensions: store.Dimensions{Length: "5", Width: "6", Height: "1", DistanceUnit: "in", Weight: "1", MassUnit: "lb", Volume: 30}},
+		&store.CartItem{ItemID: "002", SizeID: "002-OS", Quantity: 1, ShippingDimensions: store.Dimensions{Length: "6", Width: "2", Height: "3", DistanceUnit: "in", Weight: "1", MassUnit: "lb", Volume: 36}},
+	}
+
+	rp := &parcelRecorder{}
+	_, packages, _, err := createParcels(rp, packops.NewPlanner(packops.NewPacker()), packops.DefaultTableRates, items, parcels, store.CarriersUsps)
+	if err != nil {
+		t.Errorf("FAIL: %v", err)
+		return
+	}
+	if len(packages) != 1 || packages[0].ParcelID != "small" || len(rp.parcels) != 1 {
+		t.Errorf("FAIL - packages: %v; want: small", packages)
+	}
+
+	// plans failing verification create no parcels
+	rp = &parcelRecorder{}
+	_, _, _, err = createParcels(rp, failingPlanner{packops.NewPlanner(packops.NewPacker())}, packops.DefaultTableRates, items, parcels, store.CarriersUsps)
+	var perr *packops.PackingError
+	if !errors.As(err, &perr) || len(rp.parcels) != 0 {
+		t.Errorf("FAIL - verify: %v; parcels created: %d; want: PackingError, 0", err, len(rp.parcels))
+	}
+}
+
+// failingPlanner fails verification of every plan
+type failingPlanner struct {
+	packops.Planner
+}
+
+func (f failingPlanner) Verify(items []*store.CartItem, parcels []packops.PackedParcel) error {
+	return &packops.PackingError{Code: "UNITS_MISMATCH"}
+}
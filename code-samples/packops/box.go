@@ -0,0 +1,292 @@
+package packops
+
+import (
+	"fmt"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+// Box represents a 3D cubic space and is a subcomponent of the dynamic programming model
+// for filling parcels with each item in an Order. Each filled Box contains 1 PkgItem
+// bordered by 3 child Box nodes along the PkgItem's X, Y, & Z axes, derived from its remaining 3D space.
+// The Root Box Node represents the selected shipping Parcel to fill with CartItem units
+// and the whole of it's volume.
+// X, Y, & Z are the coordinates of the Box's origin corner relative to the Parcel's inner corner.
+type Box struct {
+	Volume      units.Volume
+	ResvPct     float32 // percentage of each dimension reserved for packing materials; child Box nodes lie within the reserved space
+	Length      units.Length
+	Width       units.Length
+	Height      units.Length
+	X           units.Length
+	Y           units.Length
+	Z           units.Length
+	Item        string
+	Orientation Orientation // orientation of Item within the Box
+	Score       ScoreFunc   // scoring rule used to select the Item's orientation; defaults to ScoreFirstFit
+	NodeL       *Box
+	NodeW       *Box
+	NodeH       *Box
+}
+
+// PkgItem represents an individual unit of a CartItem in an Order and the 3D space it occupies within a Box / Parcel (Box Root Node).
+// PkgItems are sorted by volume greatest to least, and are successively added to the child Nodes of a Box and their descendants
+// as remaining space permits.
+// Orientation, X, Y, & Z are set on packed items and describe how the unit sits within the Parcel.
+// Handling contains the unit's handling attributes (this-side-up, no-stack, ship-alone, fragile).
+type PkgItem struct {
+	ItemID      string
+	Name        string
+	Volume      units.Volume
+	Length      units.Length
+	Width       units.Length
+	Height      units.Length
+	Weight      units.Mass
+	Pad         units.Length // padding added to each side of fragile units
+	Shape       string       // ShapeCylinder for cylindrical units; Width and Height are the diameter
+	Handling    store.Handling
+	Orientation Orientation
+	X           units.Length
+	Y           units.Length
+	Z           units.Length
+}
+
+// Orientation represents one of the 6 axis-aligned rotations of a PkgItem within a Box.
+// Each Orientation is named by the order the item's Length (L), Width (W), and Height (H)
+// are aligned along the Box's Length, Width, and Height axes.
+type Orientation int
+
+// Orientations are listed in the order they are attempted.
+const (
+	OrientationLWH Orientation = iota // default orientation
+	OrientationWLH                    // rotated about the height axis
+	OrientationLHW
+	OrientationHLW
+	OrientationWHL
+	OrientationHWL
+)
+
+// Orientations lists all 6 axis-aligned rotations of a PkgItem.
+var Orientations = []Orientation{
+	OrientationLWH,
+	OrientationWLH,
+	OrientationLHW,
+	OrientationHLW,
+	OrientationWHL,
+	OrientationHWL,
+}
+
+func (o Orientation) String() string {
+	switch o {
+	case OrientationLWH:
+		return "LWH"
+	case OrientationWLH:
+		return "WLH"
+	case OrientationLHW:
+		return "LHW"
+	case OrientationHLW:
+		return "HLW"
+	case OrientationWHL:
+		return "WHL"
+	case OrientationHWL:
+		return "HWL"
+	}
+	return "UNKNOWN"
+}
+
+// Rotate returns the item's dimensions along the Box's Length, Width, and Height axes for the Orientation.
+func (o Orientation) Rotate(item PkgItem) (units.Length, units.Length, units.Length) {
+	l, w, h := item.Length, item.Width, item.Height
+	switch o {
+	case OrientationWLH:
+		return w, l, h
+	case OrientationLHW:
+		return l, h, w
+	case OrientationHLW:
+		return h, l, w
+	case OrientationWHL:
+		return w, h, l
+	case OrientationHWL:
+		return h, w, l
+	}
+	return l, w, h
+}
+
+// ScoreFunc scores an item with the rotated dimensions (l, w, h) placed in Box b.
+// The orientation with the lowest score is selected; ties are broken by the order of Orientations.
+type ScoreFunc func(b *Box, l, w, h units.Length) float32
+
+// ScoreFirstFit selects the first orientation in Orientations that fits the Box.
+func ScoreFirstFit(b *Box, l, w, h units.Length) float32 {
+	return 0
+}
+
+// ScoreMinHeight selects the orientation that uses the least height, keeping flat items flat.
+func ScoreMinHeight(b *Box, l, w, h units.Length) float32 {
+	return float32(h)
+}
+
+// ScoreBestFit selects the orientation that leaves the least unused space along the Box's edges.
+func ScoreBestFit(b *Box, l, w, h units.Length) float32 {
+	return float32((b.Length - l) + (b.Width - w) + (b.Height - h))
+}
+
+// Add adds an item to the current Box, and creates 3 child Box nodes.
+// Each of the 6 axis-aligned orientations of the item is compared to the dimensions of the Box,
+// and the orientation with the best score is selected from those that fit and the item's handling allows.
+// Each node represents the remaining space derived from the current Box
+// in the form of a smaller, empty Box formed along the X, Y, and Z axes of the item,
+// bounded by the dimensions of the current, occupied Box.
+// Each smaller box is recursively filled with the next largest item until there are no remaining items,
+// or there is an insufficient amount of space in the Root Box (node) for the remaining items.
+func (b *Box) Add(item PkgItem) error {
+	// the reserve is taken from the Box once; child nodes are derived from the reserved space
+	// and do not reserve again
+	resv := units.Length(b.ResvPct + 1.0)
+	bL, bW, bH := b.Length/resv, b.Width/resv, b.Height/resv
+	score := b.Score
+	if score == nil {
+		score = ScoreFirstFit
+	}
+
+	ok := false
+	best := float32(0.0)
+	var l, w, h units.Length
+	for _, o := range Orientations {
+		if !allowed(item, o) {
+			continue
+		}
+		ol, ow, oh := o.Rotate(item)
+		// mm dimensions converted from inches do not divide exactly
+		if ol > bL+epsilon || ow > bW+epsilon || oh > bH+epsilon {
+			continue
+		}
+		s := score(b, ol, ow, oh)
+		if !ok || s < best {
+			ok, best = true, s
+			l, w, h = ol, ow, oh
+			b.Orientation = o
+		}
+	}
+	if !ok {
+		// insufficient space
+		return fmt.Errorf("DIMENSIONS_EXCEEDED")
+	}
+
+	// add item to current box; create child nodes
+	b.Item = item.ItemID
+
+	// l = (Lx - Ly, Wy, Hx)
+	lBox := &Box{
+		Length: bL - l,
+		Width:  w,
+		Height: bH,
+		X:      b.X + l,
+		Y:      b.Y,
+		Z:      b.Z,
+		Score:  b.Score,
+	}
+	lBox.Volume = units.Cube(lBox.Length, lBox.Width, lBox.Height)
+
+	// w = (Lx, Wx - Wy, Hx)
+	wBox := &Box{
+		Length: bL,
+		Width:  bW - w,
+		Height: bH,
+		X:      b.X,
+		Y:      b.Y + w,
+		Z:      b.Z,
+		Score:  b.Score,
+	}
+	wBox.Volume = units.Cube(wBox.Length, wBox.Width, wBox.Height)
+
+	// h = (Ly, Wy, Hx - Hy)
+	// no space is left above no-stack items
+	hBox := &Box{
+		Length: l,
+		Width:  w,
+		Height: bH - h,
+		X:      b.X,
+		Y:      b.Y,
+		Z:      b.Z + h,
+		Score:  b.Score,
+	}
+	if item.Handling.NoStack {
+		hBox.Height = 0
+	}
+	hBox.Volume = units.Cube(hBox.Length, hBox.Width, hBox.Height)
+
+	b.NodeL, b.NodeW, b.NodeH = lBox, wBox, hBox
+
+	return nil
+}
+
+// addToBox recursively adds a list of PkgItems to a Root Box (representing a Parcel object),
+// until there are no remaining items, or there is an insufficient amount of space
+// left in the Root Box for the remaining items.
+// The remaining items and the list of packed items are returned to the caller.
+func addToBox(items []PkgItem, box *Box) ([]PkgItem, []PkgItem) {
+	rem := []PkgItem{}  // remaining items
+	pack := []PkgItem{} // packing list
+
+	if len(items) == 0 {
+		// edge case
+		return rem, pack
+	}
+	if box.Length == 0.0 || box.Width == 0.0 || box.Height == 0.0 {
+		// no remaining space along either axis
+		return items, pack
+	}
+
+	// add current item to current box; create child nodes
+	err := box.Add(items[0])
+	if err != nil {
+		// next largest does not fit; add to remaining
+		rem = append(rem, items[0])
+		if len(items) > 1 {
+			// fill with remaining items if amy
+			r, p := addToBox(items[1:], box)
+			rem = append(rem, r...)
+			pack = append(pack, p...)
+			return rem, pack
+		} else {
+			// no remaining items
+			return rem, pack
+		}
+	} else {
+		// item fits in box - add item to packing list with its placement
+		placed := items[0]
+		placed.Orientation = box.Orientation
+		placed.X, placed.Y, placed.Z = box.X, box.Y, box.Z
+		pack = append(pack, placed)
+	}
+	if len(items) > 1 {
+		// fill Length branch/Box with remaining items
+		res, pk := addToBox(items[1:], box.NodeL)
+		pack = append(pack, pk...)
+		if len(res) != 0 {
+			// Length branch does not contain sufficient space to fill total remaining items
+			// fill Width branch/Box with remaining items from Length branch
+			res, pk = addToBox(res, box.NodeW)
+			pack = append(pack, pk...)
+		} else {
+			// total of remainder of items packed into Length Branch
+			return rem, pack
+		}
+		if len(res) != 0 {
+			// Width branch does not contain sufficient space to fill remainder of
+			// items from Length Branch
+			// fill Height branch/Box with remaining items from Width branch
+			res, pk = addToBox(res, box.NodeH)
+		} else {
+			// total of remainder of items packed into Width Branch
+			return rem, pack
+		}
+
+		// add any remaining and packed items
+		rem = append(rem, res...)  // remainder of items that could not fit in Height branch (if any)
+		pack = append(pack, pk...) // items packed in Height branch (if any)
+	}
+	return rem, pack
+}
@@ -0,0 +1,237 @@
+package packops
+
+import (
+	"testing"
+
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+func TestBoxAdd(t *testing.T) {
+	var tests = []struct {
+		item     PkgItem
+		box      *Box
+		score    ScoreFunc
+		wantOr   Orientation
+		wantNode [3]units.Length // NodeH dimensions
+		wantErr  bool
+	}{
+		{ // default orientation
+			item:     PkgItem{ItemID: "001", Name: "Item 1", Length: 8.0, Width: 8.0, Height: 3.0, Volume: 192.0},
+			box:      &Box{Length: 12.0, Width: 12.0, Height: 6.0, Volume: 864.0},
+			wantOr:   OrientationLWH,
+			wantNode: [3]units.Length{8.0, 8.0, 3.0},
+		},
+		{ // flat board stored on its side - rotated onto its face
+			item:     PkgItem{ItemID: "002", Name: "Board", Length: 10.0, Width: 2.0, Height: 8.0, Volume: 160.0},
+			box:      &Box{Length: 12.0, Width: 10.0, Height: 3.0, Volume: 360.0},
+			wantOr:   OrientationLHW,
+			wantNode: [3]units.Length{10.0, 8.0, 1.0},
+		},
+		{ // fits upright, min height rule lays item flat
+			item:     PkgItem{ItemID: "003", Name: "Item 3", Length: 2.0, Width: 4.0, Height: 6.0, Volume: 48.0},
+			box:      &Box{Length: 6.0, Width: 6.0, Height: 6.0, Volume: 216.0},
+			score:    ScoreMinHeight,
+			wantOr:   OrientationWHL,
+			wantNode: [3]units.Length{4.0, 6.0, 4.0},
+		},
+		{ // does not fit in any orientation
+			item:    PkgItem{ItemID: "004", Name: "Item 4", Length: 8.0, Width: 8.0, Height: 3.0, Volume: 192.0},
+			box:     &Box{Length: 6.0, Width: 6.0, Height: 6.0, Volume: 216.0},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		test.box.Score = test.score
+		err := test.box.Add(test.item)
+		if (err != nil) != test.wantErr {
+			t.Errorf("FAIL: %v; want err: %v", err, test.wantErr)
+			continue
+		}
+		if test.wantErr {
+			continue
+		}
+		if test.box.Orientation != test.wantOr {
+			t.Errorf("FAIL - orientation: %s; want: %s", test.box.Orientation, test.wantOr)
+		}
+		h := test.box.NodeH
+		if got := [3]units.Length{h.Length, h.Width, h.Height}; got != test.wantNode {
+			t.Errorf("FAIL - NodeH: %v; want: %v", got, test.wantNode)
+		}
+		if h.Z != test.box.Height-h.Height {
+			t.Errorf("FAIL - NodeH Z: %f; want: %f", h.Z, test.box.Height-h.Height)
+		}
+	}
+}
+
+// test cases shared by TestAddToBox and the strategy comparison tests
+var addToBoxTests = []struct {
+	items   []PkgItem
+	box     *Box
+	wantIds []string
+	wantErr error
+}{
+	{
+		items: []PkgItem{
+			PkgItem{ItemID: "001", Name: "Item 1", Length: 8.0, Width: 8.0, Height: 3.0, Volume: 192.0},
+			PkgItem{ItemID: "002", Name: "Item 2", Length: 5.0, Width: 5.0, Height: 2.0, Volume: 50.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+		},
+		box: &Box{
+			Length:  12.0,
+			Width:   12.0,
+			Height:  6.0,
+			Volume:  864.0,
+			ResvPct: 0.00,
+		},
+		wantIds: []string{"001", "002", "003"},
+		wantErr: nil,
+	},
+	{
+		items: []PkgItem{
+			PkgItem{ItemID: "001", Name: "Item 1", Length: 8.0, Width: 8.0, Height: 3.0, Volume: 192.0},
+			PkgItem{ItemID: "002", Name: "Item 2", Length: 5.0, Width: 5.0, Height: 2.0, Volume: 50.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+		},
+		box: &Box{
+			Length:  12.0,
+			Width:   12.0,
+			Height:  6.0,
+			Volume:  864.0,
+			ResvPct: 0.20,
+		},
+		wantIds: []string{"001", "002", "003", "003", "003", "003", "003", "003"},
+		wantErr: nil,
+	},
+	{
+		items: []PkgItem{
+			PkgItem{ItemID: "001", Name: "Item 1", Length: 8.0, Width: 8.0, Height: 3.0, Volume: 192.0},
+			PkgItem{ItemID: "002", Name: "Item 2", Length: 5.0, Width: 5.0, Height: 2.0, Volume: 50.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+		},
+		box: &Box{
+			Length:  6.0,
+			Width:   6.0,
+			Height:  6.0,
+			Volume:  864.0,
+			ResvPct: 0.20,
+		},
+		wantIds: []string{"002", "003", "003", "003"}, // 5 x 5 x 5 reserved space
+		wantErr: nil,
+	},
+	{
+		items: []PkgItem{
+			PkgItem{ItemID: "001", Name: "Item 1", Length: 8.0, Width: 8.0, Height: 2.0, Volume: 192.0},
+			PkgItem{ItemID: "002", Name: "Item 2", Length: 5.0, Width: 5.0, Height: 2.0, Volume: 50.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+		},
+		box: &Box{
+			Length:  6.0,
+			Width:   6.0,
+			Height:  6.0,
+			Volume:  216.0,
+			ResvPct: 0.00,
+		},
+		wantIds: []string{"002", "003", "003", "003", "003"},
+		wantErr: nil,
+	},
+	{
+		items: []PkgItem{
+			PkgItem{ItemID: "002", Name: "Item 2", Length: 6.0, Width: 6.0, Height: 2.0, Volume: 72.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+		},
+		box: &Box{
+			Length:  6.0,
+			Width:   6.0,
+			Height:  6.0,
+			Volume:  216.0,
+			ResvPct: 0.00,
+		},
+		wantIds: []string{"002", "003", "003", "003", "003", "003", "003", "003", "003"},
+		wantErr: nil,
+	},
+	{
+		items: []PkgItem{
+			PkgItem{ItemID: "002", Name: "Item 2", Length: 8.0, Width: 8.0, Height: 3.0, Volume: 192.0},
+		},
+		box: &Box{
+			Length:  6.0,
+			Width:   6.0,
+			Height:  6.0,
+			Volume:  216.0,
+			ResvPct: 0.00,
+		},
+		wantIds: []string{},
+		wantErr: nil,
+	},
+	{
+		items: []PkgItem{
+			PkgItem{ItemID: "002", Name: "Item 2", Length: 6.0, Width: 6.0, Height: 2.0, Volume: 72.0},
+		},
+		box: &Box{
+			Length:  6.0,
+			Width:   6.0,
+			Height:  6.0,
+			Volume:  216.0,
+			ResvPct: 0.00,
+		},
+		wantIds: []string{"002"},
+		wantErr: nil,
+	},
+	{
+		items: []PkgItem{},
+		box: &Box{
+			Length:  6.0,
+			Width:   6.0,
+			Height:  6.0,
+			Volume:  216.0,
+			ResvPct: 0.00,
+		},
+		wantIds: []string{},
+		wantErr: nil,
+	},
+}
+
+func TestAddToBox(t *testing.T) {
+	for _, test := range addToBoxTests {
+		t.Logf("*** TEST ****")
+		rem, pack := addToBox(test.items, test.box)
+		t.Logf("rem: %v", rem)
+		t.Logf("pack: %v", pack)
+		t.Log("")
+		t.Log("")
+		if len(pack) != len(test.wantIds) {
+			t.Errorf("FAIL - data: %d; want: %d", len(pack), len(test.wantIds))
+		}
+		for i, item := range pack {
+			if item.ItemID != test.wantIds[i] {
+				t.Errorf("FAIL - data: %s; want: %s", item.ItemID, test.wantIds[i])
+			}
+			l, w, h := item.Orientation.Rotate(item)
+			if item.X+l > test.box.Length || item.Y+w > test.box.Width || item.Z+h > test.box.Height {
+				t.Errorf("FAIL - placement out of bounds: %v", item)
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package packops
+
+import (
+	"log"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+)
+
+// ParcelIDOwnContainer is the ParcelID of Packages shipped in the unit's own container.
+const ParcelIDOwnContainer = "own_container"
+
+// OwnContainerParcels returns a PackedParcel for each unit of the items that ship in their own container,
+// and the remaining items to be packed in catalog Parcels. Each PackedParcel's Parcel is created from
+// the unit's ShippingDimensions with no tare weight, and its dimensional weight is calculated for the carrier.
+func OwnContainerParcels(items []*store.CartItem, carrier string) ([]PackedParcel, []*store.CartItem, error) {
+	packed := []PackedParcel{}
+	rest := []*store.CartItem{}
+	for _, item := range items {
+		if !item.Handling.OwnContainer {
+			rest = append(rest, item)
+			continue
+		}
+
+		pkgItems, err := createPkgItems([]*store.CartItem{item})
+		if err != nil {
+			log.Printf("OwnContainerParcels failed: %v", err)
+			return []PackedParcel{}, items, err
+		}
+
+		// unit's container is the parcel
+		dims := item.ShippingDimensions
+		dims.Weight = "0.0"
+		p := &store.Parcel{
+			Carrier:          carrier,
+			ParcelID:         ParcelIDOwnContainer,
+			Name:             item.Name,
+			ParcelDimensions: dims,
+		}
+		dimWt, err := parcelDimWeight(p)
+		if err != nil {
+			log.Printf("OwnContainerParcels failed: %v", err)
+			return []PackedParcel{}, items, err
+		}
+
+		for _, unit := range pkgItems {
+			parcel := PackedParcel{
+				Parcel: p,
+				Package: store.Package{
+					Carrier:        p.Carrier,
+					ParcelID:       p.ParcelID,
+					Name:           p.Name,
+					Dimensions:     item.ShippingDimensions,
+					Items:          make(map[string]*store.PkgItemSummary),
+					ActualWeightLb: unit.Weight.Pounds(),
+					DimWeightLb:    dimWt.Pounds(),
+				},
+				Items:     []PkgItem{unit},
+				Weight:    unit.Weight,
+				DimWeight: dimWt,
+				Trace: []store.PackingDecision{
+					store.PackingDecision{ParcelID: p.ParcelID, Selected: true, Reason: TraceOwnContainer, Detail: unit.ItemID + " ships in own container"},
+				},
+			}
+			summarize(&parcel)
+			packed = append(packed, parcel)
+		}
+	}
+	return packed, rest, nil
+}
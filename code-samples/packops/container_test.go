@@ -0,0 +1,53 @@
+package packops
+
+import (
+	"testing"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+)
+
+func TestOwnContainerParcels(t *testing.T) {
+	items := []*store.CartItem{
+		&store.CartItem{
+			ItemID:             "pawnwars",
+			SizeID:             "pawnwars-retail",
+			Name:               "PawnWars",
+			Quantity:           2,
+			ShippingDimensions: store.Dimensions{Length: "12.0", Width: "12.0", Height: "3.0", DistanceUnit: "in", Weight: "2.5", MassUnit: "lb", Volume: 432.00},
+			Handling:           store.Handling{OwnContainer: true},
+		},
+		&store.CartItem{
+			ItemID:             "003",
+			SizeID:             "003-OS",
+			Quantity:           1,
+			ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
+		},
+	}
+
+	packed, rest, err := OwnContainerParcels(items, store.CarriersUsps)
+	if err != nil {
+		t.Errorf("FAIL: %v", err)
+		return
+	}
+	if len(rest) != 1 || rest[0].ItemID != "003" {
+		t.Errorf("FAIL - rest: %v; want: [003]", rest)
+	}
+	if len(packed) != 2 {
+		t.Errorf("FAIL - parcels: %d; want: 2", len(packed))
+		return
+	}
+	for _, p := range packed {
+		if p.Package.ParcelID != ParcelIDOwnContainer {
+			t.Errorf("FAIL - parcel id: %s; want: %s", p.Package.ParcelID, ParcelIDOwnContainer)
+		}
+		if p.Parcel.ParcelDimensions.Length != "12.0" || p.Parcel.ParcelDimensions.Height != "3.0" {
+			t.Errorf("FAIL - dimensions: %v", p.Parcel.ParcelDimensions)
+		}
+		if abs(p.Weight.Pounds()-2.5) > 0.01 {
+			t.Errorf("FAIL - weight: %v; want: %v", p.Weight.Pounds(), 2.5)
+		}
+		if p.Package.Items["pawnwars-retail"] == nil || p.Package.Items["pawnwars-retail"].Quantity != 1 {
+			t.Errorf("FAIL - items: %v", p.Package.Items)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package packops
+
+import (
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+// DimDivisors contains the dimensional weight divisor in cubic inches per lb for each carrier
+// and service level token. The "" service level is the carrier's default divisor.
+var DimDivisors = map[string]map[string]float32{
+	store.CarriersUsps: map[string]float32{
+		"": 166.0,
+	},
+	"ups": map[string]float32{
+		"":           139.0,
+		"ups_ground": 139.0,
+	},
+	"fedex": map[string]float32{
+		"":             139.0,
+		"fedex_ground": 139.0,
+	},
+	"dhl_express": map[string]float32{
+		"": 139.0,
+	},
+}
+
+// DimMinVolumeIn3 contains the minimum parcel volume in cubic inches dimensional weight is applied to
+// for carriers that do not apply dimensional weight to every parcel.
+var DimMinVolumeIn3 = map[string]float32{
+	store.CarriersUsps: 1728.0, // 1 cubic foot
+}
+
+// DimDivisor returns the dimensional weight divisor for the carrier and service level,
+// or the carrier's default divisor if the service level is not listed.
+// 0 is returned if the carrier does not bill by dimensional weight.
+func DimDivisor(carrier, service string) float32 {
+	divisors := DimDivisors[carrier]
+	if d, ok := divisors[service]; ok {
+		return d
+	}
+	return divisors[""]
+}
+
+// DimWeight returns the dimensional weight of a parcel with the given dimensions.
+func DimWeight(carrier, service string, l, w, h units.Length) units.Mass {
+	divisor := DimDivisor(carrier, service)
+	if divisor == 0 {
+		return 0
+	}
+	vol := units.Cube(l, w, h).CubicInches()
+	if vol <= DimMinVolumeIn3[carrier] {
+		return 0
+	}
+	return units.Mass(vol/divisor) * units.Pound
+}
+
+// BillableWeight returns the billable weight and dimensional weight of a parcel with the given
+// dimensions and actual weight. The billable weight is the greater of the actual weight
+// and the dimensional weight.
+func BillableWeight(carrier, service string, l, w, h units.Length, actual units.Mass) (units.Mass, units.Mass) {
+	dim := DimWeight(carrier, service, l, w, h)
+	if dim > actual {
+		return dim, dim
+	}
+	return actual, dim
+}
+
+// parcelDimWeight returns the dimensional weight of the parcel with the carrier's default divisor.
+// Template (flat rate) parcels are not billed by weight and return 0.
+func parcelDimWeight(p *store.Parcel) (units.Mass, error) {
+	if p.Template != "" {
+		return 0, nil
+	}
+	l, w, h, err := measure(p.ParcelDimensions)
+	if err != nil {
+		return 0, err
+	}
+	return DimWeight(p.Carrier, "", l, w, h), nil
+}
+
+// BillableWeight returns the greater of the packed parcel's actual and dimensional weight.
+func (p PackedParcel) BillableWeight() units.Mass {
+	if p.DimWeight > p.Weight {
+		return p.DimWeight
+	}
+	return p.Weight
+}
+
+// FillRatio returns the fraction of the parcel's inner volume occupied by its packed units.
+func (p PackedParcel) FillRatio() (float32, error) {
+	if p.Parcel == nil {
+		return 0, nil
+	}
+	l, w, h, err := measure(p.Parcel.ParcelDimensions)
+	if err != nil {
+		return 0, err
+	}
+	vol := units.Cube(l, w, h)
+	if vol == 0 {
+		return 0, nil
+	}
+	return float32(packedVolume(p.Items) / vol), nil
+}
@@ -0,0 +1,94 @@
+package packops
+
+import (
+	"testing"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+func TestBillableWeight(t *testing.T) {
+	var tests = []struct {
+		carrier      string
+		service      string
+		l, w, h      units.Length
+		actual       units.Mass
+		wantBillable float32
+		wantDim      float32
+	}{
+		{carrier: store.CarriersUsps, l: 12.0, w: 12.0, h: 6.0, actual: 2.0, wantBillable: 2.0, wantDim: 0.0},          // under 1 cubic foot
+		{carrier: store.CarriersUsps, l: 12.0, w: 12.0, h: 16.6, actual: 2.0, wantBillable: 14.4, wantDim: 14.4},       // DIM weight
+		{carrier: store.CarriersUsps, l: 12.0, w: 12.0, h: 16.6, actual: 20.0, wantBillable: 20.0, wantDim: 14.4},      // actual weight
+		{carrier: "ups", service: "ups_ground", l: 13.9, w: 10.0, h: 10.0, actual: 2.0, wantBillable: 10, wantDim: 10}, // every parcel
+		{carrier: "unknown", l: 12.0, w: 12.0, h: 12.0, actual: 2.0, wantBillable: 2.0, wantDim: 0.0},
+	}
+	for _, test := range tests {
+		billable, dim := BillableWeight(test.carrier, test.service, test.l*units.Inch, test.w*units.Inch, test.h*units.Inch, test.actual*units.Pound)
+		if abs(billable.Pounds()-test.wantBillable) > 0.01 {
+			t.Errorf("FAIL - billable: %f; want: %f", billable.Pounds(), test.wantBillable)
+		}
+		if abs(dim.Pounds()-test.wantDim) > 0.01 {
+			t.Errorf("FAIL - dim: %f; want: %f", dim.Pounds(), test.wantDim)
+		}
+	}
+}
+
+func TestPackBillableWeight(t *testing.T) {
+	parcels := []*store.Parcel{
+		&store.Parcel{
+			Carrier:          "ups",
+			ParcelID:         "ups_cube",
+			ParcelDimensions: store.Dimensions{Length: "12", Width: "12", Height: "12", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 1728},
+		},
+		&store.Parcel{
+			Carrier:          store.CarriersUsps,
+			ParcelID:         "usps_box",
+			ParcelDimensions: store.Dimensions{Length: "12", Width: "12", Height: "13", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 1872},
+		},
+	}
+	items := []*store.CartItem{
+		&store.CartItem{
+			ItemID:             "004",
+			SizeID:             "004-OS",
+			Quantity:           1,
+			ShippingDimensions: store.Dimensions{Length: "5", Width: "5", Height: "5", DistanceUnit: "in", Weight: "1", MassUnit: "lb", Volume: 125},
+		},
+	}
+
+	packed, err := NewPacker().Pack(items, parcels)
+	if err != nil {
+		t.Errorf("FAIL: %v", err)
+		return
+	}
+	if len(packed) != 1 {
+		t.Errorf("FAIL - parcels: %d; want: 1", len(packed))
+		return
+	}
+	p := packed[0]
+	if p.Package.ParcelID != "usps_box" {
+		t.Errorf("FAIL - parcel: %s; want: usps_box", p.Package.ParcelID)
+	}
+	if p.Package.ActualWeightLb != 1.5 {
+		t.Errorf("FAIL - actual weight: %f; want: 1.5", p.Package.ActualWeightLb)
+	}
+	if abs(p.Package.DimWeightLb-11.28) > 0.01 {
+		t.Errorf("FAIL - dim weight: %f; want: 11.28", p.Package.DimWeightLb)
+	}
+}
+
+func TestFillRatio(t *testing.T) {
+	p := PackedParcel{
+		Parcel: &store.Parcel{ParcelDimensions: store.Dimensions{Length: "4.0", Width: "4.0", Height: "4.0", DistanceUnit: "in"}},
+		Items: []PkgItem{
+			PkgItem{Length: 4.0 * units.Inch, Width: 4.0 * units.Inch, Height: 1.0 * units.Inch},
+			PkgItem{Length: 2.0 * units.Inch, Width: 2.0 * units.Inch, Height: 2.0 * units.Inch},
+		},
+	}
+	fill, err := p.FillRatio()
+	if err != nil {
+		t.Errorf("FAIL: %v", err)
+	}
+	if abs(fill-0.375) > epsilon {
+		t.Errorf("FAIL - fill: %f; want: %f", fill, 0.375)
+	}
+}
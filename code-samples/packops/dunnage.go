@@ -0,0 +1,59 @@
+package packops
+
+import (
+	"log"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+// DefaultDunnage is used for Parcels without dunnage configured.
+// 10% of each dimension is reserved for void fill.
+var DefaultDunnage = store.Dunnage{VoidFillPct: 0.1}
+
+// dunnage returns the Parcel's dunnage, or the GreedyPacker's default dunnage if the Parcel has none configured.
+func (g *GreedyPacker) dunnage(p *store.Parcel) store.Dunnage {
+	if p.Dunnage != (store.Dunnage{}) {
+		return p.Dunnage
+	}
+	return g.Dunnage
+}
+
+// padding returns the dunnage's padding on each side of the Parcel.
+func padding(d store.Dunnage) units.Length {
+	return units.Length(d.PaddingIn) * units.Inch
+}
+
+// usableDims returns the dimensions of the space available for units in the Parcel:
+// the inner dimensions less the padding on each side, less the void fill reserve.
+func usableDims(p *store.Parcel, d store.Dunnage) (units.Length, units.Length, units.Length, error) {
+	l, w, h, err := measure(p.ParcelDimensions)
+	if err != nil {
+		log.Printf("usableDims failed: %v", err)
+		return 0, 0, 0, err
+	}
+	pad := 2 * padding(d)
+	resv := units.Length(d.VoidFillPct + 1.0)
+	dims := [3]units.Length{l, w, h}
+	for i := range dims {
+		dims[i] = (dims[i] - pad) / resv
+		if dims[i] < 0 {
+			dims[i] = 0
+		}
+	}
+	return dims[0], dims[1], dims[2], nil
+}
+
+// tareWeight returns the weight of the empty Parcel with its packing materials;
+// the Parcel's weight is used if the dunnage's tare weight is not set.
+func tareWeight(p *store.Parcel, d store.Dunnage) (units.Mass, error) {
+	if d.TareWeightLb > 0 {
+		return units.Mass(d.TareWeightLb) * units.Pound, nil
+	}
+	wt, err := mass(p.ParcelDimensions)
+	if err != nil {
+		log.Printf("tareWeight failed: %v", err)
+		return 0, err
+	}
+	return wt, nil
+}
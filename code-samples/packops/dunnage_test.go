@@ -0,0 +1,86 @@
+package packops
+
+import (
+	"testing"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+func TestUsableDims(t *testing.T) {
+	var tests = []struct {
+		dunnage store.Dunnage
+		want    [3]units.Length
+	}{
+		{dunnage: store.Dunnage{}, want: [3]units.Length{304.8, 304.8, 152.4}},
+		{dunnage: store.Dunnage{PaddingIn: 1.0}, want: [3]units.Length{254.0, 254.0, 101.6}},
+		{dunnage: store.Dunnage{PaddingIn: 1.0, VoidFillPct: 0.25}, want: [3]units.Length{203.2, 203.2, 81.28}},
+		{dunnage: store.Dunnage{PaddingIn: 4.0}, want: [3]units.Length{101.6, 101.6, 0}},
+	}
+	p := &store.Parcel{
+		ParcelDimensions: store.Dimensions{Length: "12.0", Width: "12.0", Height: "6.0", DistanceUnit: "in", Weight: "1.0", MassUnit: "lb"},
+	}
+	for _, test := range tests {
+		l, w, h, err := usableDims(p, test.dunnage)
+		if err != nil {
+			t.Errorf("FAIL: %v", err)
+			continue
+		}
+		got := [3]units.Length{l, w, h}
+		for i := range got {
+			if abs(float32(got[i]-test.want[i])) > 0.01 {
+				t.Errorf("FAIL - %v: %v; want: %v", test.dunnage, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestPackDunnage(t *testing.T) {
+	items := []*store.CartItem{
+		&store.CartItem{
+			ItemID:             "003",
+			SizeID:             "003-OS",
+			Quantity:           4,
+			ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
+		},
+	}
+	var tests = []struct {
+		dunnage     store.Dunnage
+		wantParcels int
+		wantWeight  float32 // weight of first parcel in lbs
+	}{
+		{dunnage: store.Dunnage{}, wantParcels: 1, wantWeight: 2.5},                                  // default void fill
+		{dunnage: store.Dunnage{PaddingIn: 1.5, TareWeightLb: 1.5}, wantParcels: 2, wantWeight: 2.5}, // 4 x 4 x 4 padded space
+	}
+	for i, test := range tests {
+		parcels := []*store.Parcel{
+			&store.Parcel{
+				Carrier:          "usps",
+				ParcelID:         "usps_box",
+				ParcelDimensions: store.Dimensions{Length: "7.0", Width: "7.0", Height: "7.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 343.0},
+				Dunnage:          test.dunnage,
+			},
+		}
+		g := NewPacker()
+		g.Dunnage = store.Dunnage{}
+		packed, err := g.Pack(items, parcels)
+		if err != nil {
+			t.Errorf("FAIL - case %d: %v", i, err)
+			continue
+		}
+		if len(packed) != test.wantParcels {
+			t.Errorf("FAIL - case %d: parcels: %d; want: %d", i, len(packed), test.wantParcels)
+			continue
+		}
+		if abs(packed[0].Weight.Pounds()-test.wantWeight) > 0.01 {
+			t.Errorf("FAIL - case %d: weight: %v; want: %v", i, packed[0].Weight.Pounds(), test.wantWeight)
+		}
+		pad := units.Length(test.dunnage.PaddingIn) * units.Inch
+		for _, item := range packed[0].Items {
+			if item.X < pad-epsilon || item.Y < pad-epsilon || item.Z < pad-epsilon {
+				t.Errorf("FAIL - case %d: item at (%v, %v, %v) inside padding", i, item.X, item.Y, item.Z)
+			}
+		}
+	}
+}
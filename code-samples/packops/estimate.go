@@ -0,0 +1,105 @@
+package packops
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+)
+
+// RateEstimator estimates the cost of shipping a packing plan's parcels.
+type RateEstimator interface {
+	EstimatePlan(parcels []PackedParcel) (float32, error)
+}
+
+// TableRate represents the price of a parcel with a billable weight up to MaxWeightLb.
+type TableRate struct {
+	MaxWeightLb float32
+	Price       float32
+}
+
+// TableRateEstimator estimates the cost of a packing plan offline with weight tier rates per carrier
+// and flat rates per parcel template.
+type TableRateEstimator struct {
+	Rates         map[string][]TableRate // weight tiers per carrier sorted by MaxWeightLb least to greatest
+	TemplateRates map[string]float32     // flat rate price per parcel template
+}
+
+// DefaultTableRates contains approximate retail rates of USPS Priority Mail, UPS Ground, FedEx Ground,
+// and DHL Express Worldwide, so plans of each carrier shipping API can quote are ranked without rate tables.
+var DefaultTableRates = &TableRateEstimator{
+	Rates: map[string][]TableRate{
+		store.CarriersUsps: []TableRate{
+			TableRate{MaxWeightLb: 1.0, Price: 9.35},
+			TableRate{MaxWeightLb: 2.0, Price: 10.50},
+			TableRate{MaxWeightLb: 3.0, Price: 11.80},
+			TableRate{MaxWeightLb: 5.0, Price: 14.50},
+			TableRate{MaxWeightLb: 10.0, Price: 21.50},
+			TableRate{MaxWeightLb: 20.0, Price: 35.00},
+			TableRate{MaxWeightLb: 70.0, Price: 90.00},
+		},
+		"ups": []TableRate{
+			TableRate{MaxWeightLb: 1.0, Price: 12.00},
+			TableRate{MaxWeightLb: 2.0, Price: 13.50},
+			TableRate{MaxWeightLb: 3.0, Price: 14.60},
+			TableRate{MaxWeightLb: 5.0, Price: 16.50},
+			TableRate{MaxWeightLb: 10.0, Price: 22.00},
+			TableRate{MaxWeightLb: 20.0, Price: 33.00},
+			TableRate{MaxWeightLb: 70.0, Price: 85.00},
+			TableRate{MaxWeightLb: 150.0, Price: 160.00},
+		},
+		"fedex": []TableRate{
+			TableRate{MaxWeightLb: 1.0, Price: 11.80},
+			TableRate{MaxWeightLb: 2.0, Price: 13.30},
+			TableRate{MaxWeightLb: 3.0, Price: 14.40},
+			TableRate{MaxWeightLb: 5.0, Price: 16.30},
+			TableRate{MaxWeightLb: 10.0, Price: 21.70},
+			TableRate{MaxWeightLb: 20.0, Price: 32.50},
+			TableRate{MaxWeightLb: 70.0, Price: 84.00},
+			TableRate{MaxWeightLb: 150.0, Price: 158.00},
+		},
+		"dhl_express": []TableRate{
+			TableRate{MaxWeightLb: 1.0, Price: 45.00},
+			TableRate{MaxWeightLb: 2.0, Price: 52.00},
+			TableRate{MaxWeightLb: 3.0, Price: 58.00},
+			TableRate{MaxWeightLb: 5.0, Price: 70.00},
+			TableRate{MaxWeightLb: 10.0, Price: 100.00},
+			TableRate{MaxWeightLb: 20.0, Price: 150.00},
+			TableRate{MaxWeightLb: 70.0, Price: 400.00},
+		},
+	},
+	TemplateRates: map[string]float32{
+		"USPS_SmallFlatRateBox":   10.40,
+		"USPS_MediumFlatRateBox1": 17.10,
+		"USPS_LargeFlatRateBox":   22.80,
+	},
+}
+
+// EstimatePlan returns the sum of the table rates of each parcel.
+func (t *TableRateEstimator) EstimatePlan(parcels []PackedParcel) (float32, error) {
+	total := float32(0.0)
+	for _, p := range parcels {
+		price, err := t.estimateParcel(p)
+		if err != nil {
+			log.Printf("EstimatePlan failed: %v", err)
+			return 0, err
+		}
+		total += price
+	}
+	return total, nil
+}
+
+// estimateParcel returns the flat rate of the parcel's template, or the rate of the first weight tier
+// that covers the parcel's billable weight.
+func (t *TableRateEstimator) estimateParcel(p PackedParcel) (float32, error) {
+	if price, ok := t.TemplateRates[p.Package.Template]; ok && p.Package.Template != "" {
+		return price, nil
+	}
+	billable := p.BillableWeight().Pounds()
+	for _, tier := range t.Rates[p.Package.Carrier] {
+		if billable <= tier.MaxWeightLb {
+			return tier.Price, nil
+		}
+	}
+	return 0, fmt.Errorf("NO_TABLE_RATE")
+}
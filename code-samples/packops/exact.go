@@ -0,0 +1,198 @@
+package packops
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+// exactSearch holds the state of a branch-and-bound search over the order and orientation
+// of PkgItems placed at the extreme points of a Root Box.
+type exactSearch struct {
+	bL       units.Length // usable Root Box length
+	bW       units.Length // usable Root Box width
+	bH       units.Length // usable Root Box height
+	origin   point
+	deadline time.Time
+	timedOut bool
+	best     []exactItem  // packing list of best solution found
+	bestVol  units.Volume // volume packed by best solution found
+	total    units.Volume // total volume of items to pack
+	n        int          // number of items to pack
+}
+
+// identifies interchangeable units
+type unitKey struct {
+	dims     [3]units.Length
+	handling store.Handling
+}
+
+// indexed PkgItem used to restore the caller's item order after the search
+type exactItem struct {
+	PkgItem
+	index int
+	vol   units.Volume
+}
+
+// addToBoxExact searches for the packing of items in the Root Box that packs the most volume,
+// stopping as soon as every item is packed or the deadline passes.
+// The remaining items and the packing list of the best packing found are returned, and true if the search
+// completed; false is returned if the deadline passed first, in which case a better packing may exist.
+func addToBoxExact(items []PkgItem, box *Box, deadline time.Time) ([]PkgItem, []PkgItem, bool) {
+	rem := []PkgItem{}  // remaining items
+	pack := []PkgItem{} // packing list
+
+	if len(items) == 0 {
+		// edge case
+		return rem, pack, true
+	}
+
+	resv := units.Length(box.ResvPct + 1.0)
+	s := &exactSearch{
+		bL:       box.Length / resv,
+		bW:       box.Width / resv,
+		bH:       box.Height / resv,
+		origin:   point{box.X, box.Y, box.Z},
+		deadline: deadline,
+		n:        len(items),
+	}
+
+	remaining := []exactItem{}
+	for i, item := range items {
+		vol := units.Cube(item.Length, item.Width, item.Height)
+		remaining = append(remaining, exactItem{item, i, vol})
+		s.total += vol
+	}
+
+	s.search(remaining, []placement{}, []exactItem{}, []point{point{}}, 0.0, s.total)
+
+	// restore caller's item order
+	packed := make(map[int]bool)
+	for _, item := range s.best {
+		packed[item.index] = true
+	}
+	sort.SliceStable(s.best, func(i, j int) bool {
+		return s.best[i].index < s.best[j].index
+	})
+	for i, item := range items {
+		if !packed[i] {
+			rem = append(rem, item)
+		}
+	}
+	for _, item := range s.best {
+		pack = append(pack, item.PkgItem)
+	}
+
+	return rem, pack, !s.timedOut
+}
+
+// search recursively places each remaining item in each orientation at each extreme point,
+// recording the best packing list found. Branches that can not pack more volume than
+// the best packing list are pruned.
+func (s *exactSearch) search(remaining []exactItem, placed []placement, pack []exactItem, points []point, packedVol, remVol units.Volume) {
+	if moreVolume(packedVol, s.bestVol) || s.best == nil {
+		s.bestVol = packedVol
+		s.best = append([]exactItem{}, pack...)
+	}
+	if time.Now().After(s.deadline) {
+		s.timedOut = true
+		return
+	}
+	if len(remaining) == 0 || s.done() {
+		return
+	}
+
+	// bound - remaining items can not improve on best packing list
+	free := units.Cube(s.bL, s.bW, s.bH) - packedVol
+	if !moreVolume(packedVol+minVolume(remVol, free), s.bestVol) {
+		return
+	}
+
+	tried := make(map[unitKey]bool)
+	for i, item := range remaining {
+		// skip identical units already tried at this depth
+		key := unitKey{[3]units.Length{item.Length, item.Width, item.Height}, item.Handling}
+		if tried[key] {
+			continue
+		}
+		tried[key] = true
+
+		next := append(append([]exactItem{}, remaining[:i]...), remaining[i+1:]...)
+		rotations := [][3]units.Length{}
+		for _, o := range Orientations {
+			if !allowed(item.PkgItem, o) {
+				continue
+			}
+			l, w, h := o.Rotate(item.PkgItem)
+			if containsDims(rotations, [3]units.Length{l, w, h}) {
+				continue
+			}
+			rotations = append(rotations, [3]units.Length{l, w, h})
+
+			for j, pt := range points {
+				if pt.X+l > s.bL+epsilon || pt.Y+w > s.bW+epsilon || pt.Z+h > s.bH+epsilon {
+					continue
+				}
+				pl := placement{pt.X, pt.Y, pt.Z, l, w, h}
+				if overlapsAny(pl, placed) {
+					continue
+				}
+
+				p := item
+				p.Orientation = o
+				p.X, p.Y, p.Z = s.origin.X+pt.X, s.origin.Y+pt.Y, s.origin.Z+pt.Z
+
+				nextPlaced := append(append([]placement{}, placed...), pl)
+				if item.Handling.NoStack {
+					// reserve space above item
+					nextPlaced = append(nextPlaced, keepOut(pl, s.bH))
+				}
+				nextPoints := append(append([]point{}, points[:j]...), points[j+1:]...)
+				nextPoints = updateExtremePoints(nextPoints, pl, nextPlaced)
+
+				s.search(next, nextPlaced, append(append([]exactItem{}, pack...), p), nextPoints, packedVol+item.vol, remVol-item.vol)
+				if s.timedOut || s.done() {
+					return
+				}
+			}
+		}
+	}
+}
+
+// done returns true if the best packing list contains every item.
+func (s *exactSearch) done() bool {
+	return len(s.best) == s.n
+}
+
+// moreVolume returns true if volume a exceeds volume b by more than the relative volume tolerance.
+func moreVolume(a, b units.Volume) bool {
+	return a > b*(1+volTolerance)
+}
+
+// containsDims returns true if the dimensions are in the list.
+func containsDims(list [][3]units.Length, dims [3]units.Length) bool {
+	for _, d := range list {
+		if d == dims {
+			return true
+		}
+	}
+	return false
+}
+
+func minVolume(a, b units.Volume) units.Volume {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// packedVolume returns the total volume of the packed items.
+func packedVolume(items []PkgItem) units.Volume {
+	vol := units.Volume(0.0)
+	for _, item := range items {
+		vol += units.Cube(item.Length, item.Width, item.Height)
+	}
+	return vol
+}
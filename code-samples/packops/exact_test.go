@@ -0,0 +1,155 @@
+package packops
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+func TestAddToBoxExact(t *testing.T) {
+	var tests = []struct {
+		items   []PkgItem
+		box     *Box
+		budget  time.Duration
+		wantIds []string
+		wantOk  bool
+	}{
+		{ // guillotine packs 1 item; second item spans the top layer
+			items: []PkgItem{
+				PkgItem{ItemID: "001", Name: "Item 1", Length: 3.0, Width: 4.0, Height: 2.0, Volume: 24.0},
+				PkgItem{ItemID: "002", Name: "Item 2", Length: 5.0, Width: 4.0, Height: 1.0, Volume: 20.0},
+			},
+			box:     &Box{Length: 6.0, Width: 6.0, Height: 3.0, Volume: 108.0},
+			budget:  time.Second,
+			wantIds: []string{"001", "002"},
+			wantOk:  true,
+		},
+		{ // best packing leaves largest item out
+			items: []PkgItem{
+				PkgItem{ItemID: "001", Name: "Item 1", Length: 6.0, Width: 6.0, Height: 2.0, Volume: 72.0},
+				PkgItem{ItemID: "002", Name: "Item 2", Length: 4.0, Width: 4.0, Height: 4.0, Volume: 64.0},
+				PkgItem{ItemID: "003", Name: "Item 3", Length: 2.0, Width: 4.0, Height: 4.0, Volume: 32.0},
+			},
+			box:     &Box{Length: 6.0, Width: 4.0, Height: 4.0, Volume: 96.0},
+			budget:  time.Second,
+			wantIds: []string{"002", "003"},
+			wantOk:  true,
+		},
+		{ // budget exhausted; best packing found is returned
+			items: []PkgItem{
+				PkgItem{ItemID: "001", Name: "Item 1", Length: 3.0, Width: 4.0, Height: 2.0, Volume: 24.0},
+			},
+			box:     &Box{Length: 6.0, Width: 6.0, Height: 3.0, Volume: 108.0},
+			budget:  0,
+			wantIds: []string{},
+			wantOk:  false,
+		},
+		{ // parcel-sized volumes in cubic mm; every item packed
+			items: []PkgItem{
+				PkgItem{ItemID: "001", Name: "Item 1", Length: 152.4, Width: 101.6, Height: 76.2, Volume: 1179869.0},
+				PkgItem{ItemID: "002", Name: "Item 2", Length: 152.4, Width: 101.6, Height: 76.2, Volume: 1179869.0},
+				PkgItem{ItemID: "003", Name: "Item 3", Length: 152.4, Width: 203.2, Height: 76.2, Volume: 2359737.0},
+			},
+			box:     &Box{Length: 304.8, Width: 203.2, Height: 76.2, Volume: 4719475.0},
+			budget:  time.Second,
+			wantIds: []string{"001", "002", "003"},
+			wantOk:  true,
+		},
+		{ // empty list
+			items:   []PkgItem{},
+			box:     &Box{Length: 6.0, Width: 6.0, Height: 3.0, Volume: 108.0},
+			budget:  time.Second,
+			wantIds: []string{},
+			wantOk:  true,
+		},
+	}
+	for _, test := range tests {
+		rem, pack, ok := addToBoxExact(test.items, test.box, time.Now().Add(test.budget))
+		if ok != test.wantOk {
+			t.Errorf("FAIL - ok: %v; want: %v", ok, test.wantOk)
+			continue
+		}
+		if len(rem)+len(pack) != len(test.items) {
+			t.Errorf("FAIL - items: %d remaining + %d packed; want: %d", len(rem), len(pack), len(test.items))
+		}
+		if len(pack) != len(test.wantIds) {
+			t.Errorf("FAIL - data: %d; want: %d", len(pack), len(test.wantIds))
+			t.Logf("rem: %v", rem)
+			t.Logf("pack: %v", pack)
+			continue
+		}
+		for i, item := range pack {
+			if item.ItemID != test.wantIds[i] {
+				t.Errorf("FAIL - data: %s; want: %s", item.ItemID, test.wantIds[i])
+			}
+		}
+	}
+}
+
+func TestMoreVolume(t *testing.T) {
+	var tests = []struct {
+		a, b units.Volume
+		want bool
+	}{
+		{a: 1.0, b: 0.0, want: true},
+		{a: 0.0, b: 0.0, want: false},
+		{a: 50000000.0, b: 49999000.0, want: false}, // float32 rounding at parcel volumes
+		{a: 50000000.0, b: 49000000.0, want: true},
+		{a: 24.0, b: 20.0, want: true},
+	}
+	for _, test := range tests {
+		if got := moreVolume(test.a, test.b); got != test.want {
+			t.Errorf("FAIL - %f > %f: %v; want: %v", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestPackSmallOrder(t *testing.T) {
+	parcels := []*store.Parcel{
+		&store.Parcel{
+			ParcelID:         "small",
+			ParcelDimensions: store.Dimensions{Length: "8", Width: "6", Height: "4", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 192},
+		},
+		&store.Parcel{
+			ParcelID:         "large",
+			ParcelDimensions: store.Dimensions{Length: "12", Width: "12", Height: "8", DistanceUnit: "in", Weight: "1", MassUnit: "lb", Volume: 1152},
+		},
+	}
+	items := []*store.CartItem{
+		&store.CartItem{
+			ItemID:             "001",
+			SizeID:             "001-OS",
+			Quantity:           1,
+			ShippingDimensions: store.Dimensions{Length: "5", Width: "6", Height: "1", DistanceUnit: "in", Weight: "1", MassUnit: "lb", Volume: 30},
+		},
+		&store.CartItem{
+			ItemID:             "002",
+			SizeID:             "002-OS",
+			Quantity:           1,
+			ShippingDimensions: store.Dimensions{Length: "6", Width: "2", Height: "3", DistanceUnit: "in", Weight: "1", MassUnit: "lb", Volume: 36},
+		},
+	}
+	var tests = []struct {
+		exactMaxUnits int
+		budget        time.Duration
+		wantParcel    string
+	}{
+		{exactMaxUnits: 0, budget: time.Second, wantParcel: "large"}, // greedy only
+		{exactMaxUnits: 6, budget: time.Second, wantParcel: "small"},
+		{exactMaxUnits: 6, budget: 0, wantParcel: "large"}, // order deadline passed; greedy result kept
+	}
+	for _, test := range tests {
+		g := NewPacker()
+		g.ExactMaxUnits = test.exactMaxUnits
+		packed, err := g.PackBefore(items, parcels, time.Now().Add(test.budget))
+		if err != nil {
+			t.Errorf("FAIL: %v", err)
+			continue
+		}
+		if len(packed) != 1 || packed[0].Package.ParcelID != test.wantParcel {
+			t.Errorf("FAIL - parcels: %v; want: %s", packed, test.wantParcel)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package packops
+
+import (
+	"sort"
+
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+// StrategyExtremePoint fills each Parcel by placing PkgItems at the extreme points
+// (candidate corners) created by previously placed items, so leftover space is
+// shared by all remaining items rather than split into fixed child Box nodes.
+const StrategyExtremePoint Strategy = "extreme_point"
+
+// tolerance used when comparing lengths in mm & masses in g
+const epsilon = 0.001
+
+// relative tolerance used when comparing volumes; parcel volumes in cubic mm exceed the precision of epsilon
+const volTolerance = 1e-4
+
+// point represents an extreme point within a Root Box, relative to the Root Box's origin corner.
+type point struct {
+	X units.Length
+	Y units.Length
+	Z units.Length
+}
+
+// placement represents the space occupied by a placed PkgItem within a Root Box.
+type placement struct {
+	X      units.Length
+	Y      units.Length
+	Z      units.Length
+	Length units.Length
+	Width  units.Length
+	Height units.Length
+}
+
+// overlaps returns true if the placements share any volume.
+func (p placement) overlaps(o placement) bool {
+	return p.X+epsilon < o.X+o.Length && o.X+epsilon < p.X+p.Length &&
+		p.Y+epsilon < o.Y+o.Width && o.Y+epsilon < p.Y+p.Width &&
+		p.Z+epsilon < o.Z+o.Height && o.Z+epsilon < p.Z+p.Height
+}
+
+// contains returns true if the point lies within the placement's volume or on its lower faces.
+func (p placement) contains(pt point) bool {
+	return pt.X+epsilon > p.X && pt.X+epsilon < p.X+p.Length &&
+		pt.Y+epsilon > p.Y && pt.Y+epsilon < p.Y+p.Width &&
+		pt.Z+epsilon > p.Z && pt.Z+epsilon < p.Z+p.Height
+}
+
+// addToBoxExtremePoint adds a list of PkgItems to a Root Box (representing a Parcel object)
+// using extreme points. Each item is placed at the lowest, back-most extreme point where
+// one of its orientations fits without overlapping previously placed items, and the 3 corners of the
+// placed item along its X, Y, & Z axes, as well as their projections onto the nearest placed item or
+// Box wall, are added as new extreme points.
+// The remaining items and the list of packed items are returned to the caller.
+func addToBoxExtremePoint(items []PkgItem, box *Box) ([]PkgItem, []PkgItem) {
+	rem := []PkgItem{}  // remaining items
+	pack := []PkgItem{} // packing list
+
+	if len(items) == 0 {
+		// edge case
+		return rem, pack
+	}
+
+	resv := units.Length(box.ResvPct + 1.0)
+	bL, bW, bH := box.Length/resv, box.Width/resv, box.Height/resv
+	score := box.Score
+	if score == nil {
+		score = ScoreFirstFit
+	}
+
+	placed := []placement{}
+	points := []point{point{}}
+	for _, item := range items {
+		ok := false
+		var best placement
+		var bestOr Orientation
+		bestScore := float32(0.0)
+		bestIdx := 0
+		for i, pt := range points {
+			// remaining space from extreme point to Root Box bounds
+			space := &Box{Length: bL - pt.X, Width: bW - pt.Y, Height: bH - pt.Z}
+			for _, o := range Orientations {
+				if !allowed(item, o) {
+					continue
+				}
+				l, w, h := o.Rotate(item)
+				if l > space.Length+epsilon || w > space.Width+epsilon || h > space.Height+epsilon {
+					continue
+				}
+				pl := placement{pt.X, pt.Y, pt.Z, l, w, h}
+				if overlapsAny(pl, placed) {
+					continue
+				}
+				s := score(space, l, w, h)
+				if !ok || s < bestScore {
+					ok, bestScore = true, s
+					best, bestOr, bestIdx = pl, o, i
+				}
+			}
+			if ok {
+				// use first extreme point item fits in
+				break
+			}
+		}
+		if !ok {
+			// insufficient space
+			rem = append(rem, item)
+			continue
+		}
+
+		placed = append(placed, best)
+		if item.Handling.NoStack {
+			// reserve space above item
+			placed = append(placed, keepOut(best, bH))
+		}
+		item.Orientation = bestOr
+		item.X, item.Y, item.Z = box.X+best.X, box.Y+best.Y, box.Z+best.Z
+		pack = append(pack, item)
+
+		points = append(points[:bestIdx], points[bestIdx+1:]...)
+		points = updateExtremePoints(points, best, placed)
+	}
+
+	return rem, pack
+}
+
+// overlapsAny returns true if the placement overlaps any of the placed items.
+func overlapsAny(p placement, placed []placement) bool {
+	for _, o := range placed {
+		if p.overlaps(o) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateExtremePoints adds the extreme points created by the new placement p, removes any points covered
+// by placed items, and sorts the points by Z, Y, & X, least to greatest.
+func updateExtremePoints(points []point, p placement, placed []placement) []point {
+	corners := []point{
+		point{p.X + p.Length, p.Y, p.Z},
+		point{p.X, p.Y + p.Width, p.Z},
+		point{p.X, p.Y, p.Z + p.Height},
+	}
+	for _, c := range corners {
+		points = append(points, c)
+		// project corner down and back onto the nearest placed item or Box wall
+		points = append(points, project(c, placed, 1), project(c, placed, 2))
+	}
+
+	updated := []point{}
+	for _, pt := range points {
+		covered := false
+		for _, o := range placed {
+			if o.contains(pt) {
+				covered = true
+				break
+			}
+		}
+		if covered || containsPoint(updated, pt) {
+			continue
+		}
+		updated = append(updated, pt)
+	}
+
+	sort.SliceStable(updated, func(i, j int) bool {
+		a, b := updated[i], updated[j]
+		if a.Z != b.Z {
+			return a.Z < b.Z
+		}
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.X < b.X
+	})
+	return updated
+}
+
+// project moves the point along the Y (axis 1) or Z (axis 2) axis towards the origin
+// until it meets the nearest placed item or the Box wall.
+func project(pt point, placed []placement, axis int) point {
+	stop := units.Length(0.0)
+	for _, o := range placed {
+		switch axis {
+		case 1:
+			// item must span point along X & Z axes and sit behind the point
+			if pt.X+epsilon > o.X && pt.X+epsilon < o.X+o.Length && pt.Z+epsilon > o.Z && pt.Z+epsilon < o.Z+o.Height {
+				if face := o.Y + o.Width; face <= pt.Y+epsilon && face > stop {
+					stop = face
+				}
+			}
+		case 2:
+			// item must span point along X & Y axes and sit below the point
+			if pt.X+epsilon > o.X && pt.X+epsilon < o.X+o.Length && pt.Y+epsilon > o.Y && pt.Y+epsilon < o.Y+o.Width {
+				if face := o.Z + o.Height; face <= pt.Z+epsilon && face > stop {
+					stop = face
+				}
+			}
+		}
+	}
+	if axis == 1 {
+		pt.Y = stop
+	} else {
+		pt.Z = stop
+	}
+	return pt
+}
+
+// containsPoint returns true if the point is in the list.
+func containsPoint(points []point, pt point) bool {
+	for _, p := range points {
+		if abs(float32(p.X-pt.X)) < epsilon && abs(float32(p.Y-pt.Y)) < epsilon && abs(float32(p.Z-pt.Z)) < epsilon {
+			return true
+		}
+	}
+	return false
+}
+
+func abs(f float32) float32 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,156 @@
+package packops
+
+import (
+	"testing"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+// countParcels fills copies of the box with the fill function until all items are packed
+// and returns the number of parcels used, or -1 if any item does not fit an empty box.
+func countParcels(fill func([]PkgItem, *Box) ([]PkgItem, []PkgItem), items []PkgItem, box Box) int {
+	count := 0
+	for len(items) > 0 {
+		b := &Box{Length: box.Length, Width: box.Width, Height: box.Height, Volume: box.Volume, ResvPct: box.ResvPct}
+		rem, pack := fill(items, b)
+		if len(pack) == 0 {
+			return -1
+		}
+		count++
+		items = rem
+	}
+	return count
+}
+
+func TestExtremePointAddToBoxCases(t *testing.T) {
+	for i, test := range addToBoxTests {
+		want := countParcels(addToBox, test.items, *test.box)
+		got := countParcels(addToBoxExtremePoint, test.items, *test.box)
+		if want == -1 {
+			// item does not fit any parcel with either strategy
+			if got != -1 {
+				t.Errorf("FAIL - case %d: packed %d parcels; want: none", i, got)
+			}
+			continue
+		}
+		if got == -1 || got > want {
+			t.Errorf("FAIL - case %d: parcels: %d; want: <= %d", i, got, want)
+		}
+	}
+}
+
+func TestExtremePointFillParcelCases(t *testing.T) {
+	for i, test := range fillParcelTests {
+		l, w, h, err := measure(test.parcel.ParcelDimensions)
+		if err != nil {
+			t.Errorf("FAIL - measure parcel: %v", err)
+			continue
+		}
+		box := Box{Length: l, Width: w, Height: h, Volume: units.Cube(l, w, h), ResvPct: test.resv}
+		want := countParcels(addToBox, test.items, box)
+		got := countParcels(addToBoxExtremePoint, test.items, box)
+		if want == -1 {
+			if got != -1 {
+				t.Errorf("FAIL - case %d: packed %d parcels; want: none", i, got)
+			}
+			continue
+		}
+		if got == -1 || got > want {
+			t.Errorf("FAIL - case %d: parcels: %d; want: <= %d", i, got, want)
+		}
+	}
+}
+
+func TestAddToBoxExtremePoint(t *testing.T) {
+	var tests = []struct {
+		items   []PkgItem
+		box     *Box
+		wantLen int
+	}{
+		{ // guillotine NodeH only spans the first item's footprint; second item spans the top layer
+			items: []PkgItem{
+				PkgItem{ItemID: "001", Name: "Item 1", Length: 3.0, Width: 4.0, Height: 2.0, Volume: 24.0},
+				PkgItem{ItemID: "002", Name: "Item 2", Length: 5.0, Width: 4.0, Height: 1.0, Volume: 20.0},
+			},
+			box:     &Box{Length: 6.0, Width: 6.0, Height: 3.0, Volume: 108.0},
+			wantLen: 2,
+		},
+		{
+			items: []PkgItem{
+				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+				PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			},
+			box:     &Box{Length: 6.0, Width: 6.0, Height: 2.0, Volume: 72.0},
+			wantLen: 4,
+		},
+		{ // empty list
+			items:   []PkgItem{},
+			box:     &Box{Length: 6.0, Width: 6.0, Height: 6.0, Volume: 216.0},
+			wantLen: 0,
+		},
+	}
+	for _, test := range tests {
+		rem, pack := addToBoxExtremePoint(test.items, test.box)
+		if len(pack) != test.wantLen {
+			t.Errorf("FAIL - data: %d; want: %d", len(pack), test.wantLen)
+		}
+		if len(pack)+len(rem) != len(test.items) {
+			t.Errorf("FAIL - items: %d; want: %d", len(pack)+len(rem), len(test.items))
+		}
+		// verify no placed items overlap
+		for i, a := range pack {
+			l, w, h := a.Orientation.Rotate(a)
+			pa := placement{a.X, a.Y, a.Z, l, w, h}
+			if a.X+l > test.box.Length || a.Y+w > test.box.Width || a.Z+h > test.box.Height {
+				t.Errorf("FAIL - placement out of bounds: %v", a)
+			}
+			for _, b := range pack[i+1:] {
+				l, w, h := b.Orientation.Rotate(b)
+				if pa.overlaps(placement{b.X, b.Y, b.Z, l, w, h}) {
+					t.Errorf("FAIL - overlap: %v; %v", a, b)
+				}
+			}
+		}
+	}
+}
+
+func TestPackStrategy(t *testing.T) {
+	parcels := []*store.Parcel{
+		&store.Parcel{
+			Carrier:          "usps",
+			ParcelID:         "usps_squarebox",
+			Name:             "USPS Square Box",
+			ParcelDimensions: store.Dimensions{Length: "6.0", Width: "6.0", Height: "6.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 216.0},
+		},
+	}
+	items := []*store.CartItem{
+		&store.CartItem{
+			ItemID:             "003",
+			SizeID:             "003-OS",
+			Quantity:           4,
+			ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
+		},
+	}
+	// strategy set per call; the packer's Strategy is not modified
+	g := NewPacker()
+	for _, s := range []Strategy{StrategyGuillotine, StrategyExtremePoint} {
+		packed, err := g.PackWith(items, parcels, s)
+		if err != nil {
+			t.Errorf("FAIL - %s: %v", s, err)
+			continue
+		}
+		if len(packed) != 1 || len(packed[0].Items) != 4 {
+			t.Errorf("FAIL - %s: %v", s, packed)
+		}
+	}
+	if _, err := g.PackWith(items, parcels, "shelf"); err == nil || err.Error() != "INVALID_STRATEGY" {
+		t.Errorf("FAIL - shelf: %v; want: INVALID_STRATEGY", err)
+	}
+	if g.Strategy != StrategyGuillotine {
+		t.Errorf("FAIL - packer strategy: %s; want: %s", g.Strategy, StrategyGuillotine)
+	}
+}
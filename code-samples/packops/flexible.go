@@ -0,0 +1,99 @@
+package packops
+
+import (
+	"log"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+// Parcel types. Parcels with no Type are rigid boxes.
+const (
+	ParcelTypeBox    = "box"
+	ParcelTypeMailer = "mailer" // poly / padded mailer; capacity is measured by volume
+	ParcelTypeFlat   = "flat"   // flat envelope; the Parcel's Height is the thickness limit
+)
+
+// DefaultMailerGivePct is the percentage of a mailer's volume units may exceed
+// when the Parcel's GivePct is not set.
+var DefaultMailerGivePct = float32(0.15)
+
+// isFlexible returns true if the Parcel is a mailer or flat envelope.
+func isFlexible(p *store.Parcel) bool {
+	return p.Type == ParcelTypeMailer || p.Type == ParcelTypeFlat
+}
+
+// softPackable returns true if every unit may ship in a mailer or flat envelope.
+func softPackable(items []PkgItem) bool {
+	for _, item := range items {
+		if !item.Handling.SoftPack || item.Handling.Fragile {
+			return false
+		}
+	}
+	return len(items) > 0
+}
+
+// fillFlexible fills a mailer or flat envelope with the Order's items and
+// returns a list of any remaining items, the parcel's packing list, and an error value.
+// Each unit is laid flat with its smallest dimension as its height and must fit the Parcel's length and width.
+// Units are stacked until the total thickness exceeds a flat's Height, or the total volume exceeds
+// a mailer's volume plus its give.
+func (g *GreedyPacker) fillFlexible(items []PkgItem, parcel *store.Parcel) ([]PkgItem, []PkgItem, error) {
+	rem := []PkgItem{}
+	pack := []PkgItem{}
+
+	pL, pW, pH, err := measure(parcel.ParcelDimensions)
+	if err != nil {
+		log.Printf("fillFlexible failed - get parcel dimensions: %v", err)
+		return rem, pack, err
+	}
+
+	give := parcel.GivePct
+	if give == 0 {
+		give = DefaultMailerGivePct
+	}
+	capacity := units.Cube(pL, pW, pH) * units.Volume(1+give)
+
+	tare, err := tareWeight(parcel, parcel.Dunnage)
+	if err != nil {
+		log.Printf("fillFlexible failed - get parcel weight: %v", err)
+		return rem, pack, err
+	}
+	maxWt := g.maxWeight(parcel)
+
+	totalWt, thickness, volume := tare, units.Length(0.0), units.Volume(0.0)
+	for _, item := range items {
+		// lay unit flat
+		ok := false
+		h := units.Length(0.0)
+		for _, o := range Orientations {
+			if !allowed(item, o) {
+				continue
+			}
+			ol, ow, oh := o.Rotate(item)
+			if ol > pL+epsilon || ow > pW+epsilon {
+				continue
+			}
+			if !ok || oh < h {
+				ok, h = true, oh
+				item.Orientation = o
+			}
+		}
+		if !ok ||
+			(parcel.Type == ParcelTypeFlat && thickness+h > pH+epsilon) ||
+			(parcel.Type == ParcelTypeMailer && volume+item.Volume > capacity) ||
+			(maxWt > 0 && totalWt+item.Weight > maxWt) {
+			// insufficient space or weight
+			rem = append(rem, item)
+			continue
+		}
+
+		item.X, item.Y, item.Z = 0, 0, thickness
+		thickness += h
+		volume += item.Volume
+		totalWt += item.Weight
+		pack = append(pack, item)
+	}
+
+	return rem, pack, nil
+}
@@ -0,0 +1,133 @@
+package packops
+
+import (
+	"testing"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+var (
+	testFlat = &store.Parcel{
+		Carrier:          "usps",
+		ParcelID:         "usps_flat",
+		Name:             "USPS Flat Envelope",
+		Type:             ParcelTypeFlat,
+		ParcelDimensions: store.Dimensions{Length: "12.0", Width: "9.0", Height: "0.75", DistanceUnit: "in", Weight: "0.1", MassUnit: "lb"},
+	}
+	testMailer = &store.Parcel{
+		Carrier:          "usps",
+		ParcelID:         "usps_mailer",
+		Name:             "USPS Poly Mailer",
+		Type:             ParcelTypeMailer,
+		ParcelDimensions: store.Dimensions{Length: "14.0", Width: "10.0", Height: "2.5", DistanceUnit: "in", Weight: "0.1", MassUnit: "lb"},
+		GivePct:          0.1,
+	}
+	testRigid = &store.Parcel{
+		Carrier:          "usps",
+		ParcelID:         "usps_box",
+		Name:             "USPS Box",
+		ParcelDimensions: store.Dimensions{Length: "14.0", Width: "12.0", Height: "8.0", DistanceUnit: "in", Weight: "1.0", MassUnit: "lb"},
+	}
+)
+
+func TestFillFlexible(t *testing.T) {
+	sheet := PkgItem{ItemID: "sticker", Length: 10.0 * units.Inch, Width: 8.0 * units.Inch, Height: 0.25 * units.Inch, Weight: 0.1 * units.Pound}
+	sheet.Volume = units.Cube(sheet.Length, sheet.Width, sheet.Height)
+	shirt := PkgItem{ItemID: "shirt", Length: 12.0 * units.Inch, Width: 1.5 * units.Inch, Height: 9.0 * units.Inch, Weight: 0.4 * units.Pound}
+	shirt.Volume = units.Cube(shirt.Length, shirt.Width, shirt.Height)
+
+	var tests = []struct {
+		parcel   *store.Parcel
+		items    []PkgItem
+		wantPack int
+	}{
+		{parcel: testFlat, items: []PkgItem{sheet, sheet, sheet}, wantPack: 3},        // 0.75 in thick
+		{parcel: testFlat, items: []PkgItem{sheet, sheet, sheet, sheet}, wantPack: 3}, // thickness limit
+		{parcel: testFlat, items: []PkgItem{shirt}, wantPack: 0},                      // too thick
+		{parcel: testMailer, items: []PkgItem{shirt, shirt}, wantPack: 2},             // 324 in3 < 350 in3 + give
+		{parcel: testMailer, items: []PkgItem{shirt, shirt, shirt}, wantPack: 2},      // volume limit
+	}
+	g := NewPacker()
+	for i, test := range tests {
+		rem, pack, err := g.fillFlexible(test.items, test.parcel)
+		if err != nil {
+			t.Errorf("FAIL - case %d: %v", i, err)
+			continue
+		}
+		if len(pack) != test.wantPack || len(pack)+len(rem) != len(test.items) {
+			t.Errorf("FAIL - case %d: packed: %d; remaining: %d; want: %d", i, len(pack), len(rem), test.wantPack)
+		}
+		for _, item := range pack {
+			if l, w, h := item.Orientation.Rotate(item); h > l || h > w {
+				t.Errorf("FAIL - case %d: %s not laid flat: %s", i, item.ItemID, item.Orientation)
+			}
+		}
+	}
+}
+
+func TestPackFlexible(t *testing.T) {
+	var tests = []struct {
+		items      []*store.CartItem
+		wantParcel string
+	}{
+		{ // sticker sheets
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "sticker",
+					SizeID:             "sticker-OS",
+					Quantity:           2,
+					ShippingDimensions: store.Dimensions{Length: "10.0", Width: "8.0", Height: "0.25", DistanceUnit: "in", Weight: "0.1", MassUnit: "lb", Volume: 20.0},
+					Handling:           store.Handling{SoftPack: true},
+				},
+			},
+			wantParcel: "usps_flat",
+		},
+		{ // apparel
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "shirt",
+					SizeID:             "shirt-M",
+					Quantity:           2,
+					ShippingDimensions: store.Dimensions{Length: "12.0", Width: "9.0", Height: "1.5", DistanceUnit: "in", Weight: "0.4", MassUnit: "lb", Volume: 162.0},
+					Handling:           store.Handling{SoftPack: true},
+				},
+			},
+			wantParcel: "usps_mailer",
+		},
+		{ // apparel exceeds mailer capacity
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "shirt",
+					SizeID:             "shirt-M",
+					Quantity:           4,
+					ShippingDimensions: store.Dimensions{Length: "12.0", Width: "9.0", Height: "1.5", DistanceUnit: "in", Weight: "0.4", MassUnit: "lb", Volume: 162.0},
+					Handling:           store.Handling{SoftPack: true},
+				},
+			},
+			wantParcel: "usps_box",
+		},
+		{ // rigid items
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "sticker",
+					SizeID:             "sticker-OS",
+					Quantity:           2,
+					ShippingDimensions: store.Dimensions{Length: "10.0", Width: "8.0", Height: "0.25", DistanceUnit: "in", Weight: "0.1", MassUnit: "lb", Volume: 20.0},
+				},
+			},
+			wantParcel: "usps_box",
+		},
+	}
+	parcels := []*store.Parcel{testFlat, testMailer, testRigid}
+	for i, test := range tests {
+		packed, err := NewPacker().Pack(test.items, parcels)
+		if err != nil {
+			t.Errorf("FAIL - case %d: %v", i, err)
+			continue
+		}
+		if len(packed) != 1 || packed[0].Package.ParcelID != test.wantParcel {
+			t.Errorf("FAIL - case %d: %s; want: 1 x %s", i, summary(packed), test.wantParcel)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package packops
+
+import (
+	"sort"
+
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+// gridLayout describes full layers of identical units placed in a Box.
+type gridLayout struct {
+	Orientation Orientation
+	Length      units.Length // unit dimensions in Orientation
+	Width       units.Length
+	Height      units.Length
+	NumL        int // units per row
+	NumW        int // rows per layer
+	Layers      int
+}
+
+// units returns the number of units placed by the layout.
+func (gl gridLayout) units() int {
+	return gl.NumL * gl.NumW * gl.Layers
+}
+
+// fillGrid places the first run of at least GridMinUnits identical units in the Box as full grid layers,
+// then fills the space above and beside the layers with the remaining items, recursively placing
+// any further runs as grid layers. Items that are not placed in grid layers are added with fill.
+// The remaining items and the list of packed items are returned to the caller.
+func (g *GreedyPacker) fillGrid(items []PkgItem, box *Box, fill func([]PkgItem, *Box) ([]PkgItem, []PkgItem)) ([]PkgItem, []PkgItem) {
+	if g.GridMinUnits <= 0 || len(items) < g.GridMinUnits {
+		return fill(items, box)
+	}
+
+	// find first run of identical units with a full layer
+	start, end := 0, 0
+	layout := gridLayout{}
+	for start < len(items) {
+		end = runEnd(items, start)
+		if end-start >= g.GridMinUnits {
+			if gl, ok := layoutGrid(items[start], end-start, box); ok {
+				layout = gl
+				break
+			}
+		}
+		start = end
+	}
+	if layout.units() == 0 {
+		return fill(items, box)
+	}
+
+	// place full layers from the Box's origin corner
+	pack := []PkgItem{}
+	for i, item := range items[start : start+layout.units()] {
+		layer, pos := i/(layout.NumL*layout.NumW), i%(layout.NumL*layout.NumW)
+		item.Orientation = layout.Orientation
+		item.X = box.X + units.Length(pos%layout.NumL)*layout.Length
+		item.Y = box.Y + units.Length(pos/layout.NumL)*layout.Width
+		item.Z = box.Z + units.Length(layer)*layout.Height
+		pack = append(pack, item)
+	}
+	rem := append(append([]PkgItem{}, items[:start]...), items[start+layout.units():]...)
+
+	// fill space above layers, then beside layers along the Length & Width axes
+	resv := units.Length(box.ResvPct + 1.0)
+	bL, bW, bH := box.Length/resv, box.Width/resv, box.Height/resv
+	gL, gW, gH := units.Length(layout.NumL)*layout.Length, units.Length(layout.NumW)*layout.Width, units.Length(layout.Layers)*layout.Height
+	if items[start].Handling.NoStack {
+		// no space is left above no-stack units
+		gH = bH
+	}
+	children := []*Box{
+		&Box{Length: bL, Width: bW, Height: bH - gH, X: box.X, Y: box.Y, Z: box.Z + gH, Score: box.Score},
+		&Box{Length: bL - gL, Width: gW, Height: gH, X: box.X + gL, Y: box.Y, Z: box.Z, Score: box.Score},
+		&Box{Length: bL, Width: bW - gW, Height: gH, X: box.X, Y: box.Y + gW, Z: box.Z, Score: box.Score},
+	}
+	for _, child := range children {
+		if len(rem) == 0 {
+			break
+		}
+		child.Volume = units.Cube(child.Length, child.Width, child.Height)
+		if child.Volume <= 0 {
+			continue
+		}
+		r, p := g.fillGrid(rem, child, fill)
+		rem = r
+		pack = append(pack, p...)
+	}
+
+	// restore packing list order by volume greatest to least
+	sort.SliceStable(pack, func(i, j int) bool {
+		return pack[i].Volume > pack[j].Volume
+	})
+	return rem, pack
+}
+
+// runEnd returns the index after the last unit identical to items[start] in the run beginning at start.
+func runEnd(items []PkgItem, start int) int {
+	key := unitKey{[3]units.Length{items[start].Length, items[start].Width, items[start].Height}, items[start].Handling}
+	end := start + 1
+	for end < len(items) {
+		item := items[end]
+		if (unitKey{[3]units.Length{item.Length, item.Width, item.Height}, item.Handling}) != key {
+			break
+		}
+		end++
+	}
+	return end
+}
+
+// layoutGrid returns the grid layout placing the most of n identical units in full layers in the Box,
+// preferring the layout using the least height. false is returned if no full layer can be placed.
+func layoutGrid(item PkgItem, n int, box *Box) (gridLayout, bool) {
+	resv := units.Length(box.ResvPct + 1.0)
+	bL, bW, bH := box.Length/resv, box.Width/resv, box.Height/resv
+
+	best := gridLayout{}
+	for _, o := range Orientations {
+		if !allowed(item, o) {
+			continue
+		}
+		l, w, h := o.Rotate(item)
+		nl, nw, nh := int((bL+epsilon)/l), int((bW+epsilon)/w), int((bH+epsilon)/h)
+		if item.Handling.NoStack && nh > 1 {
+			nh = 1
+		}
+		if nl == 0 || nw == 0 || nh == 0 {
+			continue
+		}
+		layers := n / (nl * nw)
+		if layers > nh {
+			layers = nh
+		}
+		gl := gridLayout{o, l, w, h, nl, nw, layers}
+		if gl.units() > best.units() ||
+			(gl.units() == best.units() && units.Length(gl.Layers)*gl.Height < units.Length(best.Layers)*best.Height) {
+			best = gl
+		}
+	}
+	return best, best.units() > 0
+}
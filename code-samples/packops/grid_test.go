@@ -0,0 +1,100 @@
+package packops
+
+import (
+	"testing"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+// placements returns the space occupied by each packed item.
+func placements(pack []PkgItem) []placement {
+	placed := []placement{}
+	for _, item := range pack {
+		l, w, h := item.Orientation.Rotate(item)
+		placed = append(placed, placement{item.X, item.Y, item.Z, l, w, h})
+	}
+	return placed
+}
+
+func TestFillGrid(t *testing.T) {
+	set := PkgItem{ItemID: "chess", Length: 3.0, Width: 3.0, Height: 1.0, Volume: 9.0}
+	board := PkgItem{ItemID: "board", Length: 9.0, Width: 9.0, Height: 0.5, Volume: 40.5}
+	pawn := PkgItem{ItemID: "pawn", Length: 1.0, Width: 1.0, Height: 1.0, Volume: 1.0}
+	copies := func(item PkgItem, n int) []PkgItem {
+		items := []PkgItem{}
+		for i := 0; i < n; i++ {
+			items = append(items, item)
+		}
+		return items
+	}
+
+	var tests = []struct {
+		items    []PkgItem
+		box      Box
+		wantPack int
+	}{
+		{items: copies(set, 50), box: Box{Length: 9.0, Width: 9.0, Height: 4.0}, wantPack: 36},
+		{items: copies(set, 20), box: Box{Length: 9.0, Width: 9.0, Height: 4.0}, wantPack: 20},
+		{items: append(copies(set, 18), copies(pawn, 4)...), box: Box{Length: 10.0, Width: 9.0, Height: 2.0}, wantPack: 22}, // side strip
+		{items: append([]PkgItem{board}, copies(set, 18)...), box: Box{Length: 9.0, Width: 9.0, Height: 2.5}, wantPack: 19}, // board above layers
+		{items: copies(set, 8), box: Box{Length: 2.0, Width: 9.0, Height: 4.0}, wantPack: 6},                                // on edge
+		{items: copies(set, 8), box: Box{Length: 2.0, Width: 2.0, Height: 4.0}, wantPack: 0},
+	}
+	g := NewPacker()
+	for i, test := range tests {
+		box := test.box
+		box.Volume = units.Cube(box.Length, box.Width, box.Height)
+		rem, pack := g.fillGrid(test.items, &box, addToBox)
+		if len(pack) != test.wantPack || len(pack)+len(rem) != len(test.items) {
+			t.Errorf("FAIL - case %d: packed: %d; remaining: %d; want: %d", i, len(pack), len(rem), test.wantPack)
+		}
+		placed := placements(pack)
+		for j, p := range placed {
+			if p.X+p.Length > box.Length+epsilon || p.Y+p.Width > box.Width+epsilon || p.Z+p.Height > box.Height+epsilon {
+				t.Errorf("FAIL - case %d: item %d outside box: %v", i, j, p)
+			}
+			for k := j + 1; k < len(placed); k++ {
+				if p.overlaps(placed[k]) {
+					t.Errorf("FAIL - case %d: items %d & %d overlap: %v; %v", i, j, k, p, placed[k])
+				}
+			}
+		}
+	}
+}
+
+// benchmarkPack packs a cart of n identical units.
+func benchmarkPack(b *testing.B, n int) {
+	parcels := []*store.Parcel{
+		&store.Parcel{
+			Carrier:          "usps",
+			ParcelID:         "usps_mediumbox",
+			ParcelDimensions: store.Dimensions{Length: "12.0", Width: "12.0", Height: "8.0", DistanceUnit: "in", Weight: "1.0", MassUnit: "lb", Volume: 1152.0},
+		},
+		&store.Parcel{
+			Carrier:          "usps",
+			ParcelID:         "usps_largebox",
+			ParcelDimensions: store.Dimensions{Length: "24.0", Width: "18.0", Height: "12.0", DistanceUnit: "in", Weight: "2.0", MassUnit: "lb", Volume: 5184.0},
+		},
+	}
+	items := []*store.CartItem{
+		&store.CartItem{
+			ItemID:             "chess",
+			SizeID:             "chess-travel",
+			Quantity:           n,
+			ShippingDimensions: store.Dimensions{Length: "5.0", Width: "5.0", Height: "1.5", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 37.5},
+		},
+	}
+	g := NewPacker()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := g.Pack(items, parcels); err != nil {
+			b.Fatalf("FAIL: %v", err)
+		}
+	}
+}
+
+func BenchmarkPack1(b *testing.B)   { benchmarkPack(b, 1) }
+func BenchmarkPack10(b *testing.B)  { benchmarkPack(b, 10) }
+func BenchmarkPack100(b *testing.B) { benchmarkPack(b, 100) }
+func BenchmarkPack500(b *testing.B) { benchmarkPack(b, 500) }
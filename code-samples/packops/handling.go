@@ -0,0 +1,77 @@
+package packops
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+// Upright returns true if the Orientation keeps the item's Height along the Box's Height axis.
+func (o Orientation) Upright() bool {
+	return o == OrientationLWH || o == OrientationWLH
+}
+
+// allowed returns true if the item's handling attributes permit the Orientation.
+// this-side-up items may only be rotated about the height axis.
+func allowed(item PkgItem, o Orientation) bool {
+	return !item.Handling.ThisSideUp || o.Upright()
+}
+
+// keepOut returns the space above a no-stack item's placement, up to the top of the Root Box (height bH),
+// that no other item may be placed in.
+func keepOut(p placement, bH units.Length) placement {
+	top := p.Z + p.Height
+	return placement{p.X, p.Y, top, p.Length, p.Width, bH - top}
+}
+
+// padFragile adds padding to each side of each fragile unit.
+func padFragile(items []PkgItem, pad units.Length) []PkgItem {
+	padded := []PkgItem{}
+	for _, item := range items {
+		if item.Handling.Fragile && pad > 0 {
+			item.Length += 2 * pad
+			item.Width += 2 * pad
+			item.Height += 2 * pad
+			item.Volume = units.Cube(item.Length, item.Width, item.Height)
+			item.Pad = pad
+		}
+		padded = append(padded, item)
+	}
+	return padded
+}
+
+// splitShipAlone returns the ship-alone units and the remaining units.
+func splitShipAlone(items []PkgItem) ([]PkgItem, []PkgItem) {
+	alone := []PkgItem{}
+	rest := []PkgItem{}
+	for _, item := range items {
+		if item.Handling.ShipAlone {
+			alone = append(alone, item)
+			continue
+		}
+		rest = append(rest, item)
+	}
+	return alone, rest
+}
+
+// packAlone packs each ship-alone unit in its own parcel, and adds each parcel to the units used.
+func (g *GreedyPacker) packAlone(parcels []*store.Parcel, items []PkgItem, used map[string]int, deadline time.Time) ([]PackedParcel, error) {
+	packed := []PackedParcel{}
+	for _, item := range items {
+		parcel, rem, err := g.getParcelForVolume(parcels, []PkgItem{item}, used, deadline)
+		if err != nil {
+			log.Printf("packAlone failed: %v", err)
+			return []PackedParcel{}, err
+		}
+		if len(rem) > 0 {
+			// no parcel found for unit
+			return []PackedParcel{}, fmt.Errorf("NO_PARCEL_FOUND")
+		}
+		used[parcel.Parcel.ParcelID]++
+		packed = append(packed, parcel)
+	}
+	return packed, nil
+}
@@ -0,0 +1,144 @@
+package packops
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+)
+
+func TestHandlingAddToBox(t *testing.T) {
+	var tests = []struct {
+		items    []PkgItem
+		box      *Box
+		wantPack int
+	}{
+		{ // rotated to fit
+			items:    []PkgItem{PkgItem{ItemID: "001", Length: 2.0, Width: 2.0, Height: 5.0, Volume: 20.0}},
+			box:      &Box{Length: 6.0, Width: 6.0, Height: 3.0, Volume: 108.0},
+			wantPack: 1,
+		},
+		{ // this side up
+			items:    []PkgItem{PkgItem{ItemID: "001", Length: 2.0, Width: 2.0, Height: 5.0, Volume: 20.0, Handling: store.Handling{ThisSideUp: true}}},
+			box:      &Box{Length: 6.0, Width: 6.0, Height: 3.0, Volume: 108.0},
+			wantPack: 0,
+		},
+		{ // stacked
+			items: []PkgItem{
+				PkgItem{ItemID: "002", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+				PkgItem{ItemID: "002", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+				PkgItem{ItemID: "002", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0},
+			},
+			box:      &Box{Length: 3.0, Width: 3.0, Height: 6.0, Volume: 54.0},
+			wantPack: 3,
+		},
+		{ // no stack
+			items: []PkgItem{
+				PkgItem{ItemID: "002", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0, Handling: store.Handling{NoStack: true}},
+				PkgItem{ItemID: "002", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0, Handling: store.Handling{NoStack: true}},
+				PkgItem{ItemID: "002", Length: 3.0, Width: 3.0, Height: 2.0, Volume: 18.0, Handling: store.Handling{NoStack: true}},
+			},
+			box:      &Box{Length: 3.0, Width: 3.0, Height: 6.0, Volume: 54.0},
+			wantPack: 1,
+		},
+	}
+	fills := map[string]func([]PkgItem, *Box) ([]PkgItem, []PkgItem){
+		"guillotine":    addToBox,
+		"extreme point": addToBoxExtremePoint,
+		"exact": func(items []PkgItem, box *Box) ([]PkgItem, []PkgItem) {
+			rem, pack, _ := addToBoxExact(items, box, time.Now().Add(50*time.Millisecond))
+			return rem, pack
+		},
+	}
+	for name, fill := range fills {
+		for i, test := range tests {
+			box := *test.box
+			_, pack := fill(test.items, &box)
+			if len(pack) != test.wantPack {
+				t.Errorf("FAIL - %s case %d: packed: %d; want: %d", name, i, len(pack), test.wantPack)
+			}
+			for _, item := range pack {
+				if item.Handling.ThisSideUp && !item.Orientation.Upright() {
+					t.Errorf("FAIL - %s case %d: orientation: %s; want: upright", name, i, item.Orientation)
+				}
+			}
+		}
+	}
+}
+
+func TestPackHandling(t *testing.T) {
+	parcels := []*store.Parcel{
+		&store.Parcel{
+			Carrier:          "usps",
+			ParcelID:         "usps_box",
+			Name:             "USPS Box",
+			ParcelDimensions: store.Dimensions{Length: "7.0", Width: "7.0", Height: "7.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 343.0},
+		},
+	}
+	var tests = []struct {
+		items       []*store.CartItem
+		wantParcels int
+	}{
+		{ // packed together
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "003",
+					SizeID:             "003-OS",
+					Quantity:           4,
+					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
+				},
+			},
+			wantParcels: 1,
+		},
+		{ // ship alone
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "003",
+					SizeID:             "003-OS",
+					Quantity:           2,
+					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
+					Handling:           store.Handling{ShipAlone: true},
+				},
+				&store.CartItem{
+					ItemID:             "004",
+					SizeID:             "004-OS",
+					Quantity:           2,
+					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
+				},
+			},
+			wantParcels: 3,
+		},
+		{ // fragile padding - 5 x 5 x 4 in padded units
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "003",
+					SizeID:             "003-OS",
+					Quantity:           4,
+					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
+					Handling:           store.Handling{Fragile: true},
+				},
+			},
+			wantParcels: 4,
+		},
+	}
+	for i, test := range tests {
+		packed, err := NewPacker().Pack(test.items, parcels)
+		if err != nil {
+			t.Errorf("FAIL - case %d: %v", i, err)
+			continue
+		}
+		if len(packed) != test.wantParcels {
+			t.Errorf("FAIL - case %d: parcels: %d; want: %d", i, len(packed), test.wantParcels)
+		}
+		for _, p := range packed {
+			for _, item := range p.Items {
+				if item.Handling.ShipAlone && len(p.Items) != 1 {
+					t.Errorf("FAIL - case %d: ship alone unit packed with %d units", i, len(p.Items))
+				}
+				if item.Handling.Fragile && item.Pad == 0 {
+					t.Errorf("FAIL - case %d: fragile unit not padded", i)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,631 @@
+package packops
+
+/* packops contains the packing engine used to fit the units of an Order into shipping Parcels.
+   Each unit of each CartItem is represented as a PkgItem, and each Parcel is filled by
+   recursively adding PkgItems to a tree of Box nodes, starting with the Root Box representing
+   the Parcel's whole volume. Multi-parcel orders are split with a greedy algorithm that fits as
+   many units into the largest parcel as possible, until there is a smaller parcel that can fit
+   the remaining order volume. When multiple parcels fit the remaining units, the parcel with the
+   lowest billable weight (the greater of actual and dimensional weight) is selected.
+
+   Units are packed according to the CartItem's handling attributes: this-side-up units are only
+   rotated about their height axis, nothing is stacked on no-stack units, ship-alone units are
+   packed in their own parcel, and fragile units are padded on each side with FragilePad.
+
+   Units that may be soft packed are fitted to mailers and flat envelopes by volume and
+   thickness before rigid boxes are tried, and rolled (cylindrical) units are fitted end to end
+   in mailing tubes by diameter and length.
+
+   Space for packing materials is reserved with each Parcel's Dunnage (padding per side, void fill
+   percentage, and tare weight), or the GreedyPacker's default Dunnage, and the same usable space is
+   used to select a Parcel and to fill it.
+
+   The algorithm used to fill each Parcel is selected with the GreedyPacker's Strategy, or per call
+   with PackWith, so one GreedyPacker may pack different orders with different strategies.
+
+   Parcels with stock tracking are only selected while on-hand units remain, counting the units
+   already used for the order.
+
+   Each candidate Parcel considered for a PackedParcel is recorded in its Trace, with the reason
+   the Parcel was rejected (volume, dimensions, weight, or units left unpacked).
+
+   Dimensions and weights are parsed from the store.Dimensions of each CartItem and Parcel into
+   typed units.Length, units.Mass, & units.Volume quantities, and all packing math is done in those units.
+
+   packops is independent of any carrier API; the caller is responsible for creating carrier
+   parcel objects from the returned PackedParcels.
+*/
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/sortops"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+// Strategy represents the algorithm used to fill each Parcel with PkgItems.
+// StrategyGuillotine is used by default.
+type Strategy string
+
+// StrategyGuillotine fills each Box with 1 PkgItem and splits the remaining space into
+// 3 fixed child Box nodes along the item's X, Y, & Z axes.
+const StrategyGuillotine Strategy = "guillotine"
+
+// DefaultCarrierMaxWeightLb contains the maximum weight in lbs of a single parcel for each carrier.
+var DefaultCarrierMaxWeightLb = map[string]float32{
+	store.CarriersUsps: 70.0,
+	"ups":              150.0,
+	"fedex":            150.0,
+	"dhl_express":      154.0,
+}
+
+// Packer packs the units of an Order's CartItems into Parcels selected from the parcel catalog.
+type Packer interface {
+	Pack(items []*store.CartItem, parcels []*store.Parcel) ([]PackedParcel, error)
+}
+
+// PackedParcel represents a filled Parcel, the Package record for DB storage,
+// the Parcel's packing list, and the Parcel's total and dimensional weight.
+// Trace lists each candidate Parcel considered when the Parcel was selected, and why it was rejected.
+type PackedParcel struct {
+	Parcel    *store.Parcel
+	Package   store.Package
+	Items     []PkgItem
+	Weight    units.Mass
+	DimWeight units.Mass
+	Trace     []store.PackingDecision
+}
+
+// GreedyPacker implements the Packer interface with the greedy multi-parcel algorithm.
+// Each Parcel is filled with the configured Strategy, or the strategy passed to PackWith.
+type GreedyPacker struct {
+	Strategy Strategy
+	Score    ScoreFunc // scoring rule used to select the orientation of each unit packed in a parcel
+
+	// dunnage used for parcels without dunnage configured; applied the same way when selecting and filling a parcel
+	Dunnage store.Dunnage
+
+	// padding added to each side of fragile units
+	FragilePad units.Length
+
+	// weight caps in lbs per carrier; parcels are limited to the lesser of the carrier cap and the parcel's MaxWeightLb
+	CarrierMaxWeightLb map[string]float32
+
+	// Orders with ExactMaxUnits units or less are packed with a branch-and-bound search over
+	// item order and orientation when the Strategy can not fit every unit in a parcel.
+	// ExactBudget is the total time allowed for the searches of an order packed with Pack; once it is spent,
+	// the best packing found so far is used if it packs more than the Strategy's result.
+	// Set ExactMaxUnits to 0 to disable the search.
+	ExactMaxUnits int
+	ExactBudget   time.Duration
+
+	// Runs of GridMinUnits or more identical units are placed as full grid layers before the
+	// remaining items are packed with the Strategy. Set GridMinUnits to 0 to disable grid layers.
+	GridMinUnits int
+}
+
+// getDimensions() return type
+type dimensions struct {
+	Weight    units.Mass
+	Volume    units.Volume
+	MaxLength units.Length
+	MaxWidth  units.Length
+	MaxHeight units.Length
+	MaxWeight units.Mass // weight of heaviest unit
+}
+
+// DefaultExactBudget is the time allowed for the exact searches of an order.
+const DefaultExactBudget = 50 * time.Millisecond
+
+// NewPacker returns a GreedyPacker with the default Guillotine Strategy and packing material reserves.
+func NewPacker() *GreedyPacker {
+	return &GreedyPacker{
+		Strategy:           StrategyGuillotine,
+		Dunnage:            DefaultDunnage,
+		Score:              ScoreFirstFit,
+		ExactMaxUnits:      6,
+		CarrierMaxWeightLb: DefaultCarrierMaxWeightLb,
+		ExactBudget:        DefaultExactBudget,
+		FragilePad:         1.0 * units.Inch,
+		GridMinUnits:       8,
+	}
+}
+
+// Pack creates parcel(s) for order. Uses greedy algorithm for large multi-parcel orders to fit as many
+// objects into the largest parcel as possible (higher price : volume ratio) and fit the remainder in the smallest
+// parcel as possible and repeats as necessary for orders requring >2 parcels.
+// The order's exact searches share the GreedyPacker's ExactBudget.
+func (g *GreedyPacker) Pack(items []*store.CartItem, parcels []*store.Parcel) ([]PackedParcel, error) {
+	return g.PackBefore(items, parcels, time.Now().Add(g.ExactBudget))
+}
+
+// PackWith creates parcel(s) for order as Pack does, filling each Parcel with the strategy instead of the
+// GreedyPacker's Strategy. The GreedyPacker is not modified.
+func (g *GreedyPacker) PackWith(items []*store.CartItem, parcels []*store.Parcel, strategy Strategy) ([]PackedParcel, error) {
+	c := *g
+	c.Strategy = strategy
+	return c.Pack(items, parcels)
+}
+
+// PackBefore creates parcel(s) for order as Pack does, ending the order's exact searches at the deadline.
+// Callers packing an order several times (e.g. candidate plans) share one deadline across every call.
+func (g *GreedyPacker) PackBefore(items []*store.CartItem, parcels []*store.Parcel, deadline time.Time) ([]PackedParcel, error) {
+	packed := []PackedParcel{}
+
+	// create package item for each individual unit in order
+	pkgItems, err := createPkgItems(items)
+	if err != nil {
+		log.Printf("Pack failed: %v", err)
+		return packed, err
+	}
+	pkgItems = padFragile(pkgItems, g.FragilePad)
+
+	// units of each tracked parcel used for the order
+	used := make(map[string]int)
+
+	// pack each ship-alone unit in its own parcel
+	alone, pkgItems := splitShipAlone(pkgItems)
+	packed, err = g.packAlone(parcels, alone, used, deadline)
+	if err != nil {
+		log.Printf("Pack failed: %v", err)
+		return packed, err
+	}
+	for i := range packed {
+		summarize(&packed[i])
+	}
+	if len(pkgItems) == 0 && len(packed) > 0 {
+		return packed, nil
+	}
+
+	// create parcels for order until there is no remaining order volume
+	prevRem := 0
+	for {
+		// get parcel
+		parcel, rem, err := g.getParcelForVolume(parcels, pkgItems, used, deadline)
+		if err != nil {
+			log.Printf("Pack failed: %v", err)
+			return packed, err
+		}
+		if parcel.Parcel != nil {
+			used[parcel.Parcel.ParcelID]++
+		}
+
+		summarize(&parcel)
+		packed = append(packed, parcel)
+
+		// return if complete order packaged
+		if len(rem) == 0 {
+			return packed, nil
+		}
+
+		pkgItems = rem
+		if len(rem) == prevRem {
+			// edge case - no parcel found in list for remaining items
+			return []PackedParcel{}, fmt.Errorf("NO_PARCEL_FOUND")
+		}
+		prevRem = len(rem)
+	}
+}
+
+// create package summary / add packing list to Package
+func summarize(parcel *PackedParcel) {
+	for _, item := range parcel.Items {
+		if parcel.Package.Items[item.ItemID] == nil {
+			itemSum := &store.PkgItemSummary{
+				ItemID:   item.ItemID,
+				Name:     item.Name,
+				Quantity: 1,
+			}
+			parcel.Package.Items[item.ItemID] = itemSum
+		} else {
+			parcel.Package.Items[item.ItemID].Quantity++
+		}
+	}
+}
+
+// createPkgItems creates a PkgItem for each individual unit of each CartItem in an order,
+// sorted by volume greatest to least.
+func createPkgItems(items []*store.CartItem) ([]PkgItem, error) {
+	// split packages with greedy algorithm if total order volume is greater than largest parcel volume
+	// sort CartItems by volume greatest to least.
+	sortedByVol := sortops.SortCartItemsByUnitVolume(items)
+	pkgItems := []PkgItem{}
+	for _, item := range sortedByVol {
+		pd := item.ShippingDimensions
+		l, w, h, err := measure(pd)
+		if err != nil {
+			log.Printf("createPkgItems failed - get item dimensions: %v", err)
+			return []PkgItem{}, err
+		}
+		wt, err := mass(pd)
+		if err != nil {
+			log.Printf("createPkgItems failed - get item weight: %v", err)
+			return []PkgItem{}, err
+		}
+		for j := 0; j < item.Quantity; j++ {
+			// create PkgItem for each individual unit
+			pi := PkgItem{
+				ItemID:   item.SizeID,
+				Name:     item.Name,
+				Length:   l,
+				Width:    w,
+				Height:   h,
+				Volume:   units.Cube(l, w, h),
+				Weight:   wt,
+				Handling: item.Handling,
+				Shape:    pd.Shape,
+			}
+			pkgItems = append(pkgItems, pi)
+		}
+	}
+	return pkgItems, nil
+}
+
+// get package dimensions required to fit order
+func getDimensions(items []PkgItem) dimensions {
+	dim := dimensions{}
+
+	// calculate order volume
+	for _, item := range items {
+		l, w, h := item.Length, item.Width, item.Height
+		dim.Weight += item.Weight
+		dim.Volume += units.Cube(l, w, h)
+
+		// get max l, w, h, & unit weight
+		if l > dim.MaxLength {
+			dim.MaxLength = l
+		}
+		if w > dim.MaxWidth {
+			dim.MaxWidth = w
+		}
+		if h > dim.MaxHeight {
+			dim.MaxHeight = h
+		}
+		if item.Weight > dim.MaxWeight {
+			dim.MaxWeight = item.Weight
+		}
+	}
+
+	return dim
+}
+
+// fitsEach returns true if each item fits a space of l x w x h in at least 1 of its allowed orientations.
+// The first item that fits no orientation is returned with false.
+func fitsEach(items []PkgItem, l, w, h units.Length) (PkgItem, bool) {
+	for _, item := range items {
+		fits := false
+		for _, o := range Orientations {
+			if !allowed(item, o) {
+				continue
+			}
+			il, iw, ih := o.Rotate(item)
+			if il <= l+epsilon && iw <= w+epsilon && ih <= h+epsilon {
+				fits = true
+				break
+			}
+		}
+		if !fits {
+			return item, false
+		}
+	}
+	return PkgItem{}, true
+}
+
+// maxWeight returns the lesser of the parcel's max weight and the carrier's weight cap.
+// 0 is returned if neither limit is set.
+func (g *GreedyPacker) maxWeight(p *store.Parcel) units.Mass {
+	max := p.MaxWeightLb
+	if c := g.CarrierMaxWeightLb[p.Carrier]; c > 0 && (max == 0 || c < max) {
+		max = c
+	}
+	return units.Mass(max) * units.Pound
+}
+
+// get parcel with the lowest billable weight that fits order volume in cubic mm and weight in lbs
+// mailers and flat envelopes are tried first if every item may be soft packed, and tubes if every item is rolled
+// the largest rigid parcel is filled if the order does not fit any parcel
+// returns the filled parcel and any remaining items
+// each candidate parcel considered is recorded in the filled parcel's Trace
+// parcels out of stock after the units already used for the order are skipped
+// exact searches end at the deadline
+func (g *GreedyPacker) getParcelForVolume(parcels []*store.Parcel, pkgItems []PkgItem, used map[string]int, deadline time.Time) (PackedParcel, []PkgItem, error) {
+	rem := []PkgItem{}
+	pack := []PkgItem{}
+	sorted := sortops.SortParcelsByVolume(parcels) // sort by volume least to greatest
+	packed := PackedParcel{}
+	trace := newParcelTrace()
+
+	if len(pkgItems) == 0 {
+		log.Printf("getParcelForVolume: no items")
+		return packed, rem, nil
+	}
+
+	// get parcel dimension constraints from remaining items in mm
+	dimensions := getDimensions(pkgItems)
+	volume := dimensions.Volume
+	remaining := pkgItems // no parcel found
+	full := false         // whole order fits selected parcel
+
+	rigid := []*store.Parcel{}
+	flexible := []*store.Parcel{}
+	tubes := []*store.Parcel{}
+	for _, p := range sorted {
+		if !inStock(p, used) {
+			trace.reject(p, TraceOutOfStock, fmt.Sprintf("%d on hand; %d used", p.OnHand, used[p.ParcelID]))
+			continue
+		}
+		switch {
+		case isFlexible(p):
+			flexible = append(flexible, p)
+		case isTube(p):
+			tubes = append(tubes, p)
+		default:
+			rigid = append(rigid, p)
+		}
+	}
+
+	// try mailers and flat envelopes first for items that may be soft packed
+	if softPackable(pkgItems) {
+		candidate, rem, ok, err := g.bestFit(flexible, pkgItems, g.fillFlexible, trace)
+		if err != nil {
+			log.Printf("getParcelForVolume failed: %v", err)
+			return PackedParcel{}, rem, err
+		}
+		if ok {
+			candidate.Trace = trace.result(candidate)
+			return candidate, rem, nil
+		}
+		if len(rem) < len(remaining) {
+			packed, remaining = candidate, rem
+		}
+	}
+
+	// try tubes for rolled items
+	if rolled(pkgItems) {
+		candidate, rem, ok, err := g.bestFit(tubes, pkgItems, g.fillTube, trace)
+		if err != nil {
+			log.Printf("getParcelForVolume failed: %v", err)
+			return PackedParcel{}, rem, err
+		}
+		if ok {
+			candidate.Trace = trace.result(candidate)
+			return candidate, rem, nil
+		}
+		if len(rem) < len(remaining) {
+			packed, remaining = candidate, rem
+		}
+	}
+	sorted = rigid
+
+	// search for available parcel to fit order volume and product dimension constraints
+	for i, p := range sorted {
+		// space left for units after packing materials
+		d := g.dunnage(p)
+		l, w, h, err := usableDims(p, d)
+		if err != nil {
+			log.Printf("getParcelForVolume failed: %v", err)
+			return PackedParcel{}, rem, err
+		}
+		largest := i == len(sorted)-1
+		if volume > units.Cube(l, w, h) && !largest {
+			trace.reject(p, TraceVolumeExceeded, fmt.Sprintf("order volume %.0f in3; usable %.0f in3", volume.CubicInches(), units.Cube(l, w, h).CubicInches()))
+			continue
+		}
+
+		// order volume ok or largest parcel - verify each unit fits the parcel's usable dimensions in mm
+		// in at least 1 of its allowed orientations
+		if item, ok := fitsEach(pkgItems, l, w, h); !ok {
+			// parcel does not fit unit in any orientation
+			trace.reject(p, TraceDimensionsExceeded, fmt.Sprintf("unit %s %.2f x %.2f x %.2f in; usable %.2f x %.2f x %.2f in",
+				item.ItemID, item.Length.Inches(), item.Width.Inches(), item.Height.Inches(), l.Inches(), w.Inches(), h.Inches()))
+			continue
+		}
+
+		// get parcel wt - verify parcel can carry heaviest item
+		pWt, err := tareWeight(p, d)
+		if err != nil {
+			log.Printf("getParcelForVolume failed: %v", err)
+			return PackedParcel{}, rem, err
+		}
+		if maxWt := g.maxWeight(p); maxWt > 0 && pWt+dimensions.MaxWeight > maxWt {
+			// parcel can not carry heaviest item
+			trace.reject(p, TraceWeightExceeded, fmt.Sprintf("heaviest unit %.2f lb + tare %.2f lb; max %.2f lb", dimensions.MaxWeight.Pounds(), pWt.Pounds(), maxWt.Pounds()))
+			continue
+		}
+
+		// fill parcel
+		rem, pack, err = g.fillParcel(pkgItems, p, d, deadline)
+		if err != nil {
+			log.Printf("getParcelForVolume failed: %v", err)
+			return PackedParcel{}, rem, err
+		}
+
+		candidate, err := newPackedParcel(p, pack, d)
+		if err != nil {
+			log.Printf("getParcelForVolume failed: %v", err)
+			return PackedParcel{}, rem, err
+		}
+		trace.fill(candidate, len(rem))
+		if len(rem) > 0 {
+			if !full {
+				// try next largest parcel to attempt to fit whole order in one package
+				packed, remaining = candidate, rem
+			}
+			continue
+		}
+		// whole order fits - select parcel with lowest billable weight
+		if !full || candidate.BillableWeight() < packed.BillableWeight() {
+			packed, remaining = candidate, rem
+			full = true
+		}
+	}
+
+	packed.Trace = trace.result(packed)
+	return packed, remaining, nil
+}
+
+// fillFunc fills a Parcel with items and returns the remaining items and the packing list.
+type fillFunc func(items []PkgItem, parcel *store.Parcel) ([]PkgItem, []PkgItem, error)
+
+// bestFit fills each Parcel with fill, and returns the Parcel with the lowest billable weight that fits
+// every item, or the Parcel that fits the most items, the remaining items, and true if every item fits.
+// The Parcel's own dunnage is used; the GreedyPacker's default dunnage is not applied.
+// Each Parcel filled is recorded in the trace.
+func (g *GreedyPacker) bestFit(parcels []*store.Parcel, items []PkgItem, fill fillFunc, trace *parcelTrace) (PackedParcel, []PkgItem, bool, error) {
+	packed := PackedParcel{}
+	remaining := items
+	full := false
+	for _, p := range parcels {
+		rem, pack, err := fill(items, p)
+		if err != nil {
+			log.Printf("bestFit failed: %v", err)
+			return PackedParcel{}, items, false, err
+		}
+		if len(pack) == 0 {
+			trace.reject(p, TraceNoFit, "")
+			continue
+		}
+		candidate, err := newPackedParcel(p, pack, p.Dunnage)
+		if err != nil {
+			log.Printf("bestFit failed: %v", err)
+			return PackedParcel{}, items, false, err
+		}
+		trace.fill(candidate, len(rem))
+		if len(rem) > 0 {
+			if !full && len(rem) < len(remaining) {
+				packed, remaining = candidate, rem
+			}
+			continue
+		}
+		if !full || candidate.BillableWeight() < packed.BillableWeight() {
+			packed, remaining = candidate, rem
+			full = true
+		}
+	}
+	return packed, remaining, full, nil
+}
+
+// newPackedParcel returns the PackedParcel for the Parcel's packing list, and
+// creates the store.Package object for DB storage.
+func newPackedParcel(p *store.Parcel, pack []PkgItem, d store.Dunnage) (PackedParcel, error) {
+	totalWt, err := tareWeight(p, d)
+	if err != nil {
+		log.Printf("newPackedParcel failed: %v", err)
+		return PackedParcel{}, err
+	}
+	for _, item := range pack {
+		totalWt += item.Weight
+	}
+	dimWt, err := parcelDimWeight(p)
+	if err != nil {
+		log.Printf("newPackedParcel failed: %v", err)
+		return PackedParcel{}, err
+	}
+
+	return PackedParcel{
+		Parcel: p,
+		Package: store.Package{
+			Carrier:        p.Carrier,
+			ParcelID:       p.ParcelID,
+			Name:           p.Name,
+			Dimensions:     p.ParcelDimensions,
+			Template:       p.Template,
+			Items:          make(map[string]*store.PkgItemSummary),
+			ActualWeightLb: totalWt.Pounds(),
+			DimWeightLb:    dimWt.Pounds(),
+		},
+		Items:     pack,
+		Weight:    totalWt,
+		DimWeight: dimWt,
+	}, nil
+}
+
+// fillParcel fills the selected Parcel with the Order's items and
+// returns a list of any remaining items, the parcel's packing list, and an error value.
+// The Root Box represents the space left for units after the dunnage's padding and void fill reserve,
+// offset from the parcel's corner by the padding, so every Box in the tree is measured in usable space.
+// Small orders are searched for a better packing until the deadline.
+func (g *GreedyPacker) fillParcel(items []PkgItem, parcel *store.Parcel, dn store.Dunnage, deadline time.Time) ([]PkgItem, []PkgItem, error) {
+	rem := []PkgItem{}
+	pack := []PkgItem{}
+
+	if len(items) == 0 {
+		log.Println("fill parcel - empty item list")
+		return rem, pack, nil
+	}
+
+	mL, mW, mH, err := usableDims(parcel, dn)
+	if err != nil {
+		log.Printf("fillParcel failed - get parcel dimensions: %v", err)
+		return rem, pack, err
+	}
+	pad := padding(dn)
+
+	box := &Box{
+		Length: mL,
+		Width:  mW,
+		Height: mH,
+		Volume: units.Cube(mL, mW, mH),
+		X:      pad,
+		Y:      pad,
+		Z:      pad,
+		Score:  g.Score,
+	}
+	root := *box // empty copy of Root Box for exact search
+
+	// get remaining and packaged items from selected strategy
+	// runs of identical units are placed as grid layers first
+	var fill func([]PkgItem, *Box) ([]PkgItem, []PkgItem)
+	switch g.Strategy {
+	case StrategyGuillotine, "":
+		fill = addToBox
+	case StrategyExtremePoint:
+		fill = addToBoxExtremePoint
+	default:
+		return rem, pack, fmt.Errorf("INVALID_STRATEGY")
+	}
+	rem, pack = g.fillGrid(items, box, fill)
+
+	// search for a better packing for small orders
+	if len(rem) > 0 && len(items) <= g.ExactMaxUnits {
+		eRem, ePack, ok := addToBoxExact(items, &root, deadline)
+		if !ok {
+			log.Printf("fillParcel - exact search deadline exceeded; comparing best packing found with %s result", g.Strategy)
+		}
+		// the Strategy's result is kept if it packs as much as the search
+		if moreVolume(packedVolume(ePack), packedVolume(pack)) {
+			rem, pack = eRem, ePack
+		}
+	}
+
+	// remove items exceeding parcel weight limit
+	if maxWt := g.maxWeight(parcel); maxWt > 0 {
+		totalWt, err := tareWeight(parcel, dn)
+		if err != nil {
+			log.Printf("fillParcel failed - get parcel weight: %v", err)
+			return rem, pack, err
+		}
+		kept := []PkgItem{}
+		for _, item := range pack {
+			if totalWt+item.Weight > maxWt {
+				rem = append(rem, item)
+				continue
+			}
+			totalWt += item.Weight
+			kept = append(kept, item)
+		}
+		if len(kept) < len(pack) {
+			// restore remaining items order by volume greatest to least
+			sort.SliceStable(rem, func(i, j int) bool {
+				return rem[i].Volume > rem[j].Volume
+			})
+		}
+		pack = kept
+	}
+
+	return rem, pack, nil
+}
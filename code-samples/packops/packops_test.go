@@ -0,0 +1,775 @@
+package packops
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/dbops"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+// test cases shared by TestFillParcel and the strategy comparison tests
+var fillParcelTests = []struct {
+	parcel  *store.Parcel
+	items   []PkgItem
+	resv    float32
+	wantIds []string
+	wantErr error
+}{
+	{
+		items: []PkgItem{
+			PkgItem{ItemID: "001", Name: "Item 1", Length: 8.0 * units.Inch, Width: 8.0 * units.Inch, Height: 3.0 * units.Inch, Volume: 192.0 * units.CubicInch},
+			PkgItem{ItemID: "002", Name: "Item 2", Length: 5.0 * units.Inch, Width: 5.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 50.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+		},
+		parcel: &store.Parcel{
+			Carrier:  "usps",
+			ParcelID: "usps_largeflatratebox",
+			Name:     "USPS Large Flat Rage Box",
+			ParcelDimensions: store.Dimensions{
+				Length:       "12.0",
+				Width:        "12.0",
+				Height:       "6.0",
+				DistanceUnit: "in",
+				Weight:       "1.0",
+				MassUnit:     "lb",
+				Volume:       864.0,
+			},
+		},
+		resv:    0.2,
+		wantIds: []string{"001", "002", "003"},
+		wantErr: nil,
+	},
+	{
+		items: []PkgItem{
+			PkgItem{ItemID: "001", Name: "Item 1", Length: 8.0 * units.Inch, Width: 8.0 * units.Inch, Height: 3.0 * units.Inch, Volume: 192.0 * units.CubicInch},
+			PkgItem{ItemID: "002", Name: "Item 2", Length: 5.0 * units.Inch, Width: 5.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 50.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+		},
+		parcel: &store.Parcel{
+			Carrier:  "usps",
+			ParcelID: "usps_largeflatratebox",
+			Name:     "USPS Large Flat Rage Box",
+			ParcelDimensions: store.Dimensions{
+				Length:       "12.0",
+				Width:        "12.0",
+				Height:       "6.0",
+				DistanceUnit: "in",
+				Weight:       "1.0",
+				MassUnit:     "lb",
+				Volume:       864.0,
+			},
+		},
+		resv:    0.2,
+		wantIds: []string{"001", "002", "003", "003", "003", "003", "003", "003"},
+		wantErr: nil,
+	},
+	{
+		items: []PkgItem{
+			PkgItem{ItemID: "001", Name: "Item 1", Length: 8.0 * units.Inch, Width: 8.0 * units.Inch, Height: 3.0 * units.Inch, Volume: 192.0 * units.CubicInch},
+			PkgItem{ItemID: "002", Name: "Item 2", Length: 5.0 * units.Inch, Width: 5.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 50.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+		},
+		parcel: &store.Parcel{
+			Carrier:  "usps",
+			ParcelID: "usps_squarebox",
+			Name:     "USPS Square Box",
+			ParcelDimensions: store.Dimensions{
+				Length:       "6.0",
+				Width:        "6.0",
+				Height:       "6.0",
+				DistanceUnit: "in",
+				Weight:       "1.0",
+				MassUnit:     "lb",
+				Volume:       216.0,
+			},
+		},
+		resv:    0.2,
+		wantIds: []string{"002", "003", "003", "003"}, // 5 x 5 x 5 reserved space
+		wantErr: nil,
+	},
+	{
+		items: []PkgItem{
+			PkgItem{ItemID: "001", Name: "Item 1", Length: 8.0 * units.Inch, Width: 8.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 192.0 * units.CubicInch},
+			PkgItem{ItemID: "002", Name: "Item 2", Length: 5.0 * units.Inch, Width: 5.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 50.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+		},
+		parcel: &store.Parcel{
+			Carrier:  "usps",
+			ParcelID: "usps_squarebox",
+			Name:     "USPS Square Box",
+			ParcelDimensions: store.Dimensions{
+				Length:       "6.0",
+				Width:        "6.0",
+				Height:       "6.0",
+				DistanceUnit: "in",
+				Weight:       "1.0",
+				MassUnit:     "lb",
+				Volume:       216.0,
+			},
+		},
+		resv:    0.0,
+		wantIds: []string{"002", "003", "003", "003", "003"},
+		wantErr: nil,
+	},
+	{
+		items: []PkgItem{
+			PkgItem{ItemID: "002", Name: "Item 2", Length: 6.0 * units.Inch, Width: 6.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 72.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+			PkgItem{ItemID: "003", Name: "Item 3", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 18.0 * units.CubicInch},
+		},
+		parcel: &store.Parcel{
+			Carrier:  "usps",
+			ParcelID: "usps_squarebox",
+			Name:     "USPS Square Box",
+			ParcelDimensions: store.Dimensions{
+				Length:       "6.0",
+				Width:        "6.0",
+				Height:       "6.0",
+				DistanceUnit: "in",
+				Weight:       "1.0",
+				MassUnit:     "lb",
+				Volume:       216.0,
+			},
+		},
+		resv:    0.0,
+		wantIds: []string{"002", "003", "003", "003", "003", "003", "003", "003", "003"},
+		wantErr: nil,
+	},
+	{
+		items: []PkgItem{
+			PkgItem{ItemID: "002", Name: "Item 2", Length: 8.0 * units.Inch, Width: 8.0 * units.Inch, Height: 3.0 * units.Inch, Volume: 192.0 * units.CubicInch},
+		},
+		parcel: &store.Parcel{
+			Carrier:  "usps",
+			ParcelID: "usps_squarebox",
+			Name:     "USPS Square Box",
+			ParcelDimensions: store.Dimensions{
+				Length:       "6.0",
+				Width:        "6.0",
+				Height:       "6.0",
+				DistanceUnit: "in",
+				Weight:       "1.0",
+				MassUnit:     "lb",
+				Volume:       216.0,
+			},
+		},
+		resv:    0.0,
+		wantIds: []string{},
+		wantErr: nil,
+	},
+	{ // edge case - single item fills 100% of volume
+		items: []PkgItem{
+			PkgItem{ItemID: "002", Name: "Item 2", Length: 6.0 * units.Inch, Width: 6.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 72.0 * units.CubicInch},
+		},
+		parcel: &store.Parcel{
+			Carrier:  "usps",
+			ParcelID: "usps_squarebox",
+			Name:     "USPS Square Box",
+			ParcelDimensions: store.Dimensions{
+				Length:       "6.0",
+				Width:        "6.0",
+				Height:       "6.0",
+				DistanceUnit: "in",
+				Weight:       "1.0",
+				MassUnit:     "lb",
+				Volume:       216.0,
+			},
+		},
+		resv:    0.0,
+		wantIds: []string{"002"},
+		wantErr: nil,
+	},
+	{ // edge case - empty list
+		items: []PkgItem{},
+		parcel: &store.Parcel{
+			Carrier:  "usps",
+			ParcelID: "usps_squarebox",
+			Name:     "USPS Square Box",
+			ParcelDimensions: store.Dimensions{
+				Length:       "6.0",
+				Width:        "6.0",
+				Height:       "6.0",
+				DistanceUnit: "in",
+				Weight:       "1.0",
+				MassUnit:     "lb",
+				Volume:       216.0,
+			},
+		},
+		resv:    0.0,
+		wantIds: []string{},
+		wantErr: nil,
+	},
+}
+
+func TestFillParcel(t *testing.T) {
+	for _, test := range fillParcelTests {
+		g := NewPacker()
+		rem, pack, err := g.fillParcel(test.items, test.parcel, store.Dunnage{VoidFillPct: test.resv}, time.Now().Add(g.ExactBudget))
+		if err != test.wantErr {
+			t.Errorf("FAIL: %v; want: %v", err, test.wantErr)
+		}
+		if len(pack) != len(test.wantIds) {
+			t.Errorf("FAIL - data: %d; want: %d", len(pack), len(test.wantIds))
+			t.Logf("items: %v", test.items)
+			t.Logf("rem: %v", rem)
+			t.Logf("pack: %v", pack)
+			return
+		}
+		for i, item := range pack {
+			if item.ItemID != test.wantIds[i] {
+				t.Errorf("FAIL - data: %s; want: %s", item.ItemID, test.wantIds[i])
+			}
+		}
+		t.Logf("rem: %v", rem)
+		t.Logf("pack: %v", pack)
+	}
+}
+
+func TestGetDimensions(t *testing.T) {
+	var tests = []struct {
+		items  []*store.CartItem
+		length float32
+		width  float32
+		height float32
+		weight float32
+		volume float32
+	}{
+		{
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "001",
+					SizeID:             "001-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "8.0", Width: "8.0", Height: "3.0", DistanceUnit: "in", Weight: "1.0", MassUnit: "lb", Volume: 192.00},
+				},
+				&store.CartItem{
+					ItemID:             "002",
+					SizeID:             "002-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "5.0", Width: "5.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 50.00},
+				},
+				&store.CartItem{
+					ItemID:             "001",
+					SizeID:             "001-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
+				},
+			},
+			length: 8.0,
+			width:  8.0,
+			height: 3.0,
+			weight: 2.0,
+			volume: 260,
+		},
+		{
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "001",
+					SizeID:             "001-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "8.0", Width: "8.0", Height: "3.0", DistanceUnit: "in", Weight: "1.0", MassUnit: "lb", Volume: 192.00},
+				},
+				&store.CartItem{
+					ItemID:             "002",
+					SizeID:             "002-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "5.0", Width: "5.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 50.00},
+				},
+				&store.CartItem{
+					ItemID:             "001",
+					SizeID:             "001-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
+				},
+				&store.CartItem{
+					ItemID:             "001",
+					SizeID:             "001-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
+				},
+				&store.CartItem{
+					ItemID:             "001",
+					SizeID:             "001-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
+				},
+				&store.CartItem{
+					ItemID:             "001",
+					SizeID:             "001-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
+				},
+			},
+			length: 8.0,
+			width:  8.0,
+			height: 3.0,
+			weight: 3.5,
+			volume: 314,
+		},
+		{
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "002",
+					SizeID:             "002-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "5.0", Width: "5.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 50.00},
+				},
+			},
+			length: 5.0,
+			width:  5.0,
+			height: 2.0,
+			weight: 0.5,
+			volume: 50.0,
+		},
+		{
+			items:  []*store.CartItem{},
+			length: 0.0,
+			width:  0.0,
+			height: 0.0,
+			weight: 0.0,
+			volume: 0.0,
+		},
+	}
+	for _, test := range tests {
+		pkgItems, err := createPkgItems(test.items)
+		if err != nil {
+			t.Errorf("FAIL: %v", err)
+			return
+		}
+		d := getDimensions(pkgItems)
+		if abs(d.MaxLength.Inches()-test.length) > 0.01 {
+			t.Errorf("FAIL - length: %f; want: %f", d.MaxLength.Inches(), test.length)
+		}
+		if abs(d.MaxWidth.Inches()-test.width) > 0.01 {
+			t.Errorf("FAIL - width: %f; want: %f", d.MaxWidth.Inches(), test.width)
+		}
+		if abs(d.MaxHeight.Inches()-test.height) > 0.01 {
+			t.Errorf("FAIL - height: %f; want: %f", d.MaxHeight.Inches(), test.height)
+		}
+		if abs(d.Weight.Pounds()-test.weight) > 0.01 {
+			t.Errorf("FAIL - weight: %f; want: %f", d.Weight.Pounds(), test.weight)
+		}
+		if abs(d.Volume.CubicInches()-test.volume) > 0.1 {
+			t.Errorf("FAIL - volume: %f; want: %f", d.Volume.CubicInches(), test.volume)
+		}
+	}
+}
+
+func TestGetParcelForVolume(t *testing.T) {
+	var tests = []struct {
+		items   []*store.CartItem
+		resv    float32
+		wantErr error
+	}{
+		{
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "001",
+					SizeID:             "001-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "8.0", Width: "8.0", Height: "3.0", DistanceUnit: "in", Weight: "1.0", MassUnit: "lb", Volume: 192.00},
+				},
+				&store.CartItem{
+					ItemID:             "002",
+					SizeID:             "002-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "5.0", Width: "5.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 50.00},
+				},
+				&store.CartItem{
+					ItemID:             "003",
+					SizeID:             "003-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
+				},
+			},
+			resv:    0.0,
+			wantErr: nil,
+		},
+		{
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "001",
+					SizeID:             "001-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "8.0", Width: "8.0", Height: "3.0", DistanceUnit: "in", Weight: "1.0", MassUnit: "lb", Volume: 192.00},
+				},
+				&store.CartItem{
+					ItemID:             "002",
+					SizeID:             "002-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "5.0", Width: "5.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 50.00},
+				},
+				&store.CartItem{
+					ItemID:             "003",
+					SizeID:             "003-OS",
+					Quantity:           4,
+					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
+				},
+			},
+			resv:    0.2,
+			wantErr: nil,
+		},
+		{
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "001",
+					SizeID:             "001-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "8.0", Width: "8.0", Height: "3.0", DistanceUnit: "in", Weight: "1.0", MassUnit: "lb", Volume: 192.00},
+				},
+				&store.CartItem{
+					ItemID:             "002",
+					SizeID:             "002-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "5.0", Width: "5.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 50.00},
+				},
+				&store.CartItem{
+					ItemID:             "003",
+					SizeID:             "003-OS",
+					Quantity:           4,
+					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
+				},
+			},
+			resv:    0.0,
+			wantErr: nil,
+		},
+		{
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "002",
+					SizeID:             "002-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "6.0", Width: "6.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 50.00},
+				},
+				&store.CartItem{
+					ItemID:             "003",
+					SizeID:             "003-OS",
+					Quantity:           8,
+					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
+				},
+			},
+			resv:    0.0,
+			wantErr: nil,
+		},
+		{
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "002",
+					SizeID:             "002-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "6.0", Width: "6.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 50.00},
+				},
+				&store.CartItem{
+					ItemID:             "003",
+					SizeID:             "003-OS",
+					Quantity:           8,
+					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
+				},
+			},
+			resv:    0.2,
+			wantErr: nil,
+		},
+		{ // 1 large flate rate + 1 square
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "002",
+					SizeID:             "002-OS",
+					Quantity:           4,
+					ShippingDimensions: store.Dimensions{Length: "5.0", Width: "5.0", Height: "5.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 125.00},
+				},
+				&store.CartItem{
+					ItemID:             "003",
+					SizeID:             "003-OS",
+					Quantity:           10,
+					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
+				},
+			},
+			resv:    0.0,
+			wantErr: nil,
+		},
+		{
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "002",
+					SizeID:             "002-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "5.0", Width: "5.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 50.00},
+				},
+			},
+			resv:    0.0,
+			wantErr: nil,
+		},
+		{
+			items:   []*store.CartItem{},
+			resv:    0.0,
+			wantErr: nil,
+		},
+	}
+
+	os.Setenv(dbops.EnvarParcelsTable, "acamoprjct-parcels-dev")
+	os.Setenv(dbops.EnvarStoreItemsIndexTable, "acamoprjct-store-items-index-dev")
+	table1 := dbops.NewTable(dbops.ParcelsTable(), dbops.ParcelsPK, "")
+	table2 := dbops.NewTable(dbops.StoreItemsIndexTable(), dbops.StoreItemsIndexPK, "")
+	tables := []dbops.Table{table1, table2}
+	dbInfo := dbops.InitDB(tables)
+
+	indexKey := "parcels-usps"
+	index, err := dbops.GetStoreItemIndex(dbInfo, indexKey)
+	if err != nil {
+		t.Errorf("FAIL - index: %v", err)
+		return
+	}
+	t.Logf("index: %v", index.ItemIDs)
+	parcels, err := dbops.BatchGetParcels(dbInfo, index.ItemIDs)
+	if err != nil {
+		t.Errorf("FAIL - get parcels: %v", err)
+		return
+	}
+	t.Logf("%v", parcels)
+
+	g := NewPacker()
+	for _, test := range tests {
+		pkgItems, err := createPkgItems(test.items)
+		if err != nil {
+			t.Errorf("FAIL - get items: %v", err)
+			return
+		}
+		g.Dunnage = store.Dunnage{VoidFillPct: test.resv}
+		parcel, rem, err := g.getParcelForVolume(parcels, pkgItems, nil, time.Now().Add(g.ExactBudget))
+		if err != test.wantErr {
+			t.Errorf("FAIL: %v; want: %v", err, test.wantErr)
+			return
+		}
+		t.Logf("parcel: %v", parcel.Parcel)
+		t.Logf("package: %v", parcel.Package)
+		t.Logf("rem: %v", rem)
+		t.Logf("pack: %v", parcel.Items)
+	}
+}
+
+func TestPack(t *testing.T) {
+	parcels := []*store.Parcel{
+		&store.Parcel{
+			Carrier:          "usps",
+			ParcelID:         "usps_largeflatratebox",
+			Name:             "USPS Large Flat Rate Box",
+			ParcelDimensions: store.Dimensions{Length: "12.0", Width: "12.0", Height: "6.0", DistanceUnit: "in", Weight: "1.0", MassUnit: "lb", Volume: 864.0},
+		},
+		&store.Parcel{
+			Carrier:          "usps",
+			ParcelID:         "usps_squarebox",
+			Name:             "USPS Square Box",
+			ParcelDimensions: store.Dimensions{Length: "6.0", Width: "6.0", Height: "6.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 216.0},
+		},
+	}
+	var tests = []struct {
+		items       []*store.CartItem
+		wantParcels []string
+		wantWt      []float32
+		wantErr     error
+	}{
+		{ // 1 square box
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "002",
+					SizeID:             "002-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "5.0", Width: "5.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 50.00},
+				},
+			},
+			wantParcels: []string{"usps_squarebox"},
+			wantWt:      []float32{1.0},
+			wantErr:     nil,
+		},
+		{ // 1 large flat rate box
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "001",
+					SizeID:             "001-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "8.0", Width: "8.0", Height: "3.0", DistanceUnit: "in", Weight: "1.0", MassUnit: "lb", Volume: 192.00},
+				},
+				&store.CartItem{
+					ItemID:             "003",
+					SizeID:             "003-OS",
+					Quantity:           2,
+					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
+				},
+			},
+			wantParcels: []string{"usps_largeflatratebox"},
+			wantWt:      []float32{3.0},
+			wantErr:     nil,
+		},
+	}
+
+	for _, test := range tests {
+		packed, err := NewPacker().Pack(test.items, parcels)
+		if err != test.wantErr {
+			t.Errorf("FAIL: %v; want: %v", err, test.wantErr)
+			continue
+		}
+		if len(packed) != len(test.wantParcels) {
+			t.Errorf("FAIL - parcels: %d; want: %d", len(packed), len(test.wantParcels))
+			continue
+		}
+		for i, p := range packed {
+			if p.Package.ParcelID != test.wantParcels[i] {
+				t.Errorf("FAIL - parcel: %s; want: %s", p.Package.ParcelID, test.wantParcels[i])
+			}
+			if abs(p.Weight.Pounds()-test.wantWt[i]) > 0.01 {
+				t.Errorf("FAIL - weight: %f; want: %f", p.Weight.Pounds(), test.wantWt[i])
+			}
+		}
+	}
+}
+
+func TestPackWeight(t *testing.T) {
+	var tests = []struct {
+		parcel    *store.Parcel
+		items     []*store.CartItem
+		wantUnits []int // units per parcel
+		wantErr   error
+	}{
+		{ // parcel max weight
+			parcel: &store.Parcel{
+				Carrier:          "usps",
+				ParcelID:         "usps_largeflatratebox",
+				ParcelDimensions: store.Dimensions{Length: "12.0", Width: "12.0", Height: "6.0", DistanceUnit: "in", Weight: "1.0", MassUnit: "lb", Volume: 864.0},
+				MaxWeightLb:      20.0,
+			},
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "005",
+					SizeID:             "005-OS",
+					Quantity:           4,
+					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "8.0", MassUnit: "lb", Volume: 18.00},
+				},
+			},
+			wantUnits: []int{2, 2},
+			wantErr:   nil,
+		},
+		{ // carrier weight cap
+			parcel: &store.Parcel{
+				Carrier:          store.CarriersUsps,
+				ParcelID:         "usps_largeflatratebox",
+				ParcelDimensions: store.Dimensions{Length: "12.0", Width: "12.0", Height: "6.0", DistanceUnit: "in", Weight: "1.0", MassUnit: "lb", Volume: 864.0},
+			},
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "005",
+					SizeID:             "005-OS",
+					Quantity:           3,
+					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "30.0", MassUnit: "lb", Volume: 18.00},
+				},
+			},
+			wantUnits: []int{2, 1},
+			wantErr:   nil,
+		},
+		{ // unit exceeds weight limit
+			parcel: &store.Parcel{
+				Carrier:          "usps",
+				ParcelID:         "usps_largeflatratebox",
+				ParcelDimensions: store.Dimensions{Length: "12.0", Width: "12.0", Height: "6.0", DistanceUnit: "in", Weight: "1.0", MassUnit: "lb", Volume: 864.0},
+				MaxWeightLb:      20.0,
+			},
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "005",
+					SizeID:             "005-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "25.0", MassUnit: "lb", Volume: 18.00},
+				},
+			},
+			wantUnits: []int{},
+			wantErr:   fmt.Errorf("NO_PARCEL_FOUND"),
+		},
+	}
+	for _, test := range tests {
+		packed, err := NewPacker().Pack(test.items, []*store.Parcel{test.parcel})
+		if fmt.Sprint(err) != fmt.Sprint(test.wantErr) {
+			t.Errorf("FAIL: %v; want: %v", err, test.wantErr)
+			continue
+		}
+		if len(packed) != len(test.wantUnits) {
+			t.Errorf("FAIL - parcels: %d; want: %d", len(packed), len(test.wantUnits))
+			continue
+		}
+		for i, p := range packed {
+			if len(p.Items) != test.wantUnits[i] {
+				t.Errorf("FAIL - units: %d; want: %d", len(p.Items), test.wantUnits[i])
+			}
+			if max := NewPacker().maxWeight(test.parcel); p.Weight > max {
+				t.Errorf("FAIL - weight: %f; want: <= %f", p.Weight.Pounds(), max.Pounds())
+			}
+		}
+	}
+}
+
+func TestPackRotatedUnits(t *testing.T) {
+	parcels := []*store.Parcel{
+		&store.Parcel{
+			Carrier:          store.CarriersUsps,
+			ParcelID:         "long",
+			ParcelDimensions: store.Dimensions{Length: "12.0", Width: "8.0", Height: "4.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 384.0},
+		},
+		&store.Parcel{
+			Carrier:          store.CarriersUsps,
+			ParcelID:         "tall",
+			ParcelDimensions: store.Dimensions{Length: "10.0", Width: "10.0", Height: "12.0", DistanceUnit: "in", Weight: "1.0", MassUnit: "lb", Volume: 1200.0},
+		},
+	}
+	var tests = []struct {
+		handling   store.Handling
+		wantParcel string
+	}{
+		{handling: store.Handling{}, wantParcel: "long"},                 // units stored upright are laid on their side
+		{handling: store.Handling{ThisSideUp: true}, wantParcel: "tall"}, // units must stay upright
+	}
+	for _, test := range tests {
+		// more units than the exact search packs
+		items := []*store.CartItem{
+			&store.CartItem{
+				ItemID:             "010",
+				SizeID:             "010-OS",
+				Quantity:           8,
+				Handling:           test.handling,
+				ShippingDimensions: store.Dimensions{Length: "2.0", Width: "2.0", Height: "10.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 40.0},
+			},
+		}
+		g := NewPacker()
+		g.Dunnage = store.Dunnage{}
+		packed, err := g.Pack(items, parcels)
+		if err != nil {
+			t.Errorf("FAIL: %v", err)
+			continue
+		}
+		if len(packed) != 1 || packed[0].Package.ParcelID != test.wantParcel {
+			t.Errorf("FAIL - parcels: %v; want: %s", packed, test.wantParcel)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package packops
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+)
+
+// Placements returns the placement of each unit in the packed parcel for storage on the store.Package.
+// Positions and dimensions are in inches, measured from the parcel's inner corner, and
+// placements are sorted in packing order: bottom to top, back to front, and left to right.
+// Fragile units are placed inside their padding, with their unpadded dimensions.
+// index is the position of the parcel in the Shipment's Packages.
+func Placements(p PackedParcel, index int) []store.Placement {
+	placements := []store.Placement{}
+	for _, item := range p.Items {
+		l, w, h := item.Orientation.Rotate(item)
+		pad := item.Pad
+		placements = append(placements, store.Placement{
+			ParcelIndex:  index,
+			ItemID:       item.ItemID,
+			Name:         item.Name,
+			Orientation:  item.Orientation.String(),
+			X:            (item.X + pad).Inches(),
+			Y:            (item.Y + pad).Inches(),
+			Z:            (item.Z + pad).Inches(),
+			Length:       (l - 2*pad).Inches(),
+			Width:        (w - 2*pad).Inches(),
+			Height:       (h - 2*pad).Inches(),
+			DistanceUnit: "in",
+		})
+	}
+	sort.SliceStable(placements, func(i, j int) bool {
+		a, b := placements[i], placements[j]
+		if a.Z != b.Z {
+			return a.Z < b.Z
+		}
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.X < b.X
+	})
+	return placements
+}
+
+// Instructions returns the packing instructions for each unit of each Package, in packing order.
+func Instructions(pkgs []store.Package) []string {
+	lines := []string{}
+	for _, pkg := range pkgs {
+		for _, pl := range pkg.Placements {
+			lines = append(lines, fmt.Sprintf("parcel %d (%s): %s %s at (%.2f, %.2f, %.2f) %s; oriented %s (%.2f x %.2f x %.2f %s)",
+				pl.ParcelIndex+1, pkg.ParcelID, pl.ItemID, pl.Name, pl.X, pl.Y, pl.Z, pl.DistanceUnit,
+				pl.Orientation, pl.Length, pl.Width, pl.Height, pl.DistanceUnit))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,96 @@
+package packops
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+)
+
+func TestPlacements(t *testing.T) {
+	parcels := []*store.Parcel{
+		&store.Parcel{
+			Carrier:          "usps",
+			ParcelID:         "usps_squarebox",
+			ParcelDimensions: store.Dimensions{Length: "6.0", Width: "6.0", Height: "6.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 216.0},
+			Dunnage:          store.Dunnage{PaddingIn: 0.5},
+		},
+	}
+	items := []*store.CartItem{
+		&store.CartItem{
+			ItemID:             "003",
+			SizeID:             "003-OS",
+			Name:               "Item 3",
+			Quantity:           4,
+			ShippingDimensions: store.Dimensions{Length: "2.5", Width: "2.5", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 12.5},
+		},
+	}
+	packed, err := NewPacker().Pack(items, parcels)
+	if err != nil || len(packed) != 1 {
+		t.Errorf("FAIL - pack: %v; parcels: %d", err, len(packed))
+		return
+	}
+
+	placements := Placements(packed[0], 2)
+	if len(placements) != 4 {
+		t.Errorf("FAIL - placements: %d; want: 4", len(placements))
+		return
+	}
+	for i, pl := range placements {
+		if pl.ParcelIndex != 2 || pl.ItemID != "003-OS" || pl.DistanceUnit != "in" {
+			t.Errorf("FAIL - placement %d: %+v", i, pl)
+		}
+		if pl.X < 0.5-epsilon || pl.Y < 0.5-epsilon || pl.Z < 0.5-epsilon {
+			t.Errorf("FAIL - placement %d inside padding: %+v", i, pl)
+		}
+		if i > 0 && pl.Z < placements[i-1].Z {
+			t.Errorf("FAIL - placement %d out of packing order: %+v", i, pl)
+		}
+	}
+
+	pkg := packed[0].Package
+	pkg.Placements = placements
+	lines := Instructions([]store.Package{pkg})
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "parcel 3 (usps_squarebox): 003-OS Item 3 at (0.50, 0.50, 0.50) in") {
+		t.Errorf("FAIL - instructions: %v", lines)
+	}
+}
+
+func TestPlacementsFragile(t *testing.T) {
+	parcels := []*store.Parcel{
+		&store.Parcel{
+			Carrier:          "usps",
+			ParcelID:         "usps_squarebox",
+			ParcelDimensions: store.Dimensions{Length: "6.0", Width: "6.0", Height: "6.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 216.0},
+		},
+	}
+	items := []*store.CartItem{
+		&store.CartItem{
+			ItemID:             "009",
+			SizeID:             "009-OS",
+			Name:               "Vase",
+			Quantity:           1,
+			Handling:           store.Handling{Fragile: true},
+			ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "3.0", DistanceUnit: "in", Weight: "1", MassUnit: "lb", Volume: 27.0},
+		},
+	}
+	g := NewPacker()
+	g.Dunnage = store.Dunnage{}
+	packed, err := g.Pack(items, parcels)
+	if err != nil || len(packed) != 1 {
+		t.Errorf("FAIL - pack: %v; parcels: %d", err, len(packed))
+		return
+	}
+
+	// unit is placed inside its 1 in padding with its own dimensions
+	placements := Placements(packed[0], 0)
+	if len(placements) != 1 {
+		t.Errorf("FAIL - placements: %d; want: 1", len(placements))
+		return
+	}
+	pl := placements[0]
+	if abs(pl.X-1.0) > epsilon || abs(pl.Y-1.0) > epsilon || abs(pl.Z-1.0) > epsilon ||
+		abs(pl.Length-3.0) > epsilon || abs(pl.Width-3.0) > epsilon || abs(pl.Height-3.0) > epsilon {
+		t.Errorf("FAIL - placement: %+v; want: 3 x 3 x 3 in at (1, 1, 1)", pl)
+	}
+}
@@ -0,0 +1,189 @@
+package packops
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/sortops"
+)
+
+// Plan represents a candidate packing plan for an order and its estimated shipping cost.
+// Reason describes why the plan was selected.
+type Plan struct {
+	Name    string
+	Parcels []PackedParcel
+	Cost    float32
+	Reason  string
+}
+
+// Planner selects the cheapest packing plan for an order from several candidate plans
+// priced with the RateEstimator; the candidates share the order's packing deadline.
+// Verify returns an error if the plan's parcels can not be packed as placed or do not contain every ordered unit.
+type Planner interface {
+	Plan(items []*store.CartItem, parcels []*store.Parcel, est RateEstimator, deadline time.Time) (Plan, error)
+	Verify(items []*store.CartItem, parcels []PackedParcel) error
+}
+
+// PlanPacker implements the Planner interface. Candidate plans are generated by packing the order
+// with the GreedyPacker against subsets of the parcel catalog:
+//   - the whole catalog (greedy plan)
+//   - the catalog capped at each parcel size (e.g. two medium boxes instead of one large box)
+//   - each parcel size alone
+type PlanPacker struct {
+	Packer *GreedyPacker
+}
+
+// NewPlanner returns a PlanPacker that packs candidate plans with the GreedyPacker.
+func NewPlanner(g *GreedyPacker) *PlanPacker {
+	return &PlanPacker{Packer: g}
+}
+
+// Pack implements the Packer interface and returns the parcels of the cheapest plan priced with DefaultTableRates.
+func (pp *PlanPacker) Pack(items []*store.CartItem, parcels []*store.Parcel) ([]PackedParcel, error) {
+	plan, err := pp.Plan(items, parcels, DefaultTableRates, time.Now().Add(pp.Packer.ExactBudget))
+	if err != nil {
+		log.Printf("Pack failed: %v", err)
+		return []PackedParcel{}, err
+	}
+	return plan.Parcels, nil
+}
+
+// Plan returns the cheapest candidate plan for the order. The greedy plan is returned if
+// est is nil or no candidate plan could be priced. Exact searches of every candidate end at the deadline.
+func (pp *PlanPacker) Plan(items []*store.CartItem, parcels []*store.Parcel, est RateEstimator, deadline time.Time) (Plan, error) {
+	candidates, err := pp.candidates(items, parcels, deadline)
+	if err != nil {
+		log.Printf("Plan failed: %v", err)
+		return Plan{}, err
+	}
+	greedy := candidates[0]
+
+	if est == nil {
+		greedy.Reason = "greedy plan selected; no rate estimator"
+		return greedy, nil
+	}
+
+	priced := []Plan{}
+	for _, c := range candidates {
+		cost, err := est.EstimatePlan(c.Parcels)
+		if err != nil {
+			log.Printf("Plan - estimate %s failed: %v", c.Name, err)
+			continue
+		}
+		c.Cost = cost
+		priced = append(priced, c)
+	}
+	if len(priced) == 0 {
+		greedy.Reason = "greedy plan selected; rate estimates failed"
+		return greedy, nil
+	}
+
+	// cheapest plan first; ties broken by fewest parcels, then candidate order
+	sort.SliceStable(priced, func(i, j int) bool {
+		if priced[i].Cost != priced[j].Cost {
+			return priced[i].Cost < priced[j].Cost
+		}
+		return len(priced[i].Parcels) < len(priced[j].Parcels)
+	})
+
+	best := priced[0]
+	best.Reason = fmt.Sprintf("%s plan (%s) selected at $%.2f; cheapest of %d priced plans", best.Name, summary(best.Parcels), best.Cost, len(priced))
+	if len(priced) > 1 {
+		next := priced[1]
+		best.Reason += fmt.Sprintf("; next: %s plan (%s) at $%.2f", next.Name, summary(next.Parcels), next.Cost)
+	}
+	log.Printf("Plan: %s", best.Reason)
+
+	return best, nil
+}
+
+// PackOrder returns the cheapest packing plan for the order's items selected by the Planner, with a parcel
+// for each unit that ships in its own container appended to the plan's parcels. Packing ends at the order's deadline.
+func PackOrder(pl Planner, est RateEstimator, items []*store.CartItem, parcels []*store.Parcel, carrier string, deadline time.Time) (Plan, error) {
+	own, rest, err := OwnContainerParcels(items, carrier)
+	if err != nil {
+		log.Printf("PackOrder failed: %v", err)
+		return Plan{}, err
+	}
+
+	plan := Plan{Name: "own container", Reason: "all units ship in own container"}
+	if len(rest) > 0 {
+		plan, err = pl.Plan(rest, parcels, est, deadline)
+		if err != nil {
+			log.Printf("PackOrder failed: %v", err)
+			return plan, err
+		}
+	}
+	plan.Parcels = append(plan.Parcels, own...)
+
+	return plan, nil
+}
+
+// Verify verifies the plan's parcels with the GreedyPacker's dunnage and weight limits.
+func (pp *PlanPacker) Verify(items []*store.CartItem, parcels []PackedParcel) error {
+	return pp.Packer.Verify(items, parcels)
+}
+
+// candidates returns the distinct candidate plans for the order, beginning with the greedy plan.
+// An error is returned if the greedy plan fails.
+func (pp *PlanPacker) candidates(items []*store.CartItem, parcels []*store.Parcel, deadline time.Time) ([]Plan, error) {
+	plans := []Plan{}
+	seen := make(map[string]bool)
+
+	add := func(name string, catalog []*store.Parcel) error {
+		packed, err := pp.Packer.PackBefore(items, catalog, deadline)
+		if err != nil {
+			return err
+		}
+		key := summary(packed)
+		if seen[key] {
+			return nil
+		}
+		seen[key] = true
+		plans = append(plans, Plan{Name: name, Parcels: packed})
+		return nil
+	}
+
+	// greedy plan
+	if err := add("greedy", parcels); err != nil {
+		return plans, err
+	}
+
+	sorted := sortops.SortParcelsByVolume(parcels) // sort by volume least to greatest
+	for i, p := range sorted {
+		// catalog capped at parcel size
+		if i < len(sorted)-1 {
+			if err := add("max "+p.ParcelID, sorted[:i+1]); err != nil {
+				log.Printf("candidates - max %s: %v", p.ParcelID, err)
+			}
+		}
+		// parcel size alone
+		if err := add("only "+p.ParcelID, []*store.Parcel{p}); err != nil {
+			log.Printf("candidates - only %s: %v", p.ParcelID, err)
+		}
+	}
+
+	return plans, nil
+}
+
+// summary returns the count of each parcel in the plan, e.g. "2 x usps_mediumbox".
+func summary(parcels []PackedParcel) string {
+	counts := make(map[string]int)
+	ids := []string{}
+	for _, p := range parcels {
+		if counts[p.Package.ParcelID] == 0 {
+			ids = append(ids, p.Package.ParcelID)
+		}
+		counts[p.Package.ParcelID]++
+	}
+	sort.Strings(ids)
+	s := []string{}
+	for _, id := range ids {
+		s = append(s, fmt.Sprintf("%d x %s", counts[id], id))
+	}
+	return strings.Join(s, ", ")
+}
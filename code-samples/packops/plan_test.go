@@ -0,0 +1,168 @@
+package packops
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+func TestPlan(t *testing.T) {
+	parcels := []*store.Parcel{
+		&store.Parcel{
+			Carrier:          store.CarriersUsps,
+			ParcelID:         "medium",
+			Template:         "M",
+			ParcelDimensions: store.Dimensions{Length: "7", Width: "7", Height: "6", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 294},
+		},
+		&store.Parcel{
+			Carrier:          store.CarriersUsps,
+			ParcelID:         "large",
+			Template:         "L",
+			ParcelDimensions: store.Dimensions{Length: "14", Width: "8", Height: "6", DistanceUnit: "in", Weight: "1", MassUnit: "lb", Volume: 672},
+		},
+	}
+	items := []*store.CartItem{
+		&store.CartItem{
+			ItemID:             "006",
+			SizeID:             "006-OS",
+			Quantity:           2,
+			ShippingDimensions: store.Dimensions{Length: "6", Width: "6", Height: "5", DistanceUnit: "in", Weight: "2", MassUnit: "lb", Volume: 180},
+		},
+	}
+	var tests = []struct {
+		est         RateEstimator
+		wantParcels []string
+		wantCost    float32
+	}{
+		{ // 2 medium boxes cheaper than 1 large box
+			est:         &TableRateEstimator{TemplateRates: map[string]float32{"M": 12.0, "L": 30.0}},
+			wantParcels: []string{"medium", "medium"},
+			wantCost:    24.0,
+		},
+		{ // 1 large box cheaper than 2 medium boxes
+			est:         &TableRateEstimator{TemplateRates: map[string]float32{"M": 16.0, "L": 30.0}},
+			wantParcels: []string{"large"},
+			wantCost:    30.0,
+		},
+		{ // no estimator - greedy plan
+			est:         nil,
+			wantParcels: []string{"large"},
+			wantCost:    0.0,
+		},
+		{ // rates missing - greedy plan
+			est:         &TableRateEstimator{},
+			wantParcels: []string{"large"},
+			wantCost:    0.0,
+		},
+	}
+	for _, test := range tests {
+		plan, err := NewPlanner(NewPacker()).Plan(items, parcels, test.est, time.Now().Add(DefaultExactBudget))
+		if err != nil {
+			t.Errorf("FAIL: %v", err)
+			continue
+		}
+		if len(plan.Parcels) != len(test.wantParcels) {
+			t.Errorf("FAIL - parcels: %s; want: %v", summary(plan.Parcels), test.wantParcels)
+			continue
+		}
+		for i, p := range plan.Parcels {
+			if p.Package.ParcelID != test.wantParcels[i] {
+				t.Errorf("FAIL - parcel: %s; want: %s", p.Package.ParcelID, test.wantParcels[i])
+			}
+		}
+		if plan.Cost != test.wantCost {
+			t.Errorf("FAIL - cost: %f; want: %f", plan.Cost, test.wantCost)
+		}
+		if !strings.Contains(plan.Reason, "selected") {
+			t.Errorf("FAIL - reason: %s", plan.Reason)
+		}
+		t.Logf("reason: %s", plan.Reason)
+	}
+}
+
+// countingEstimator records the parcels of each plan priced
+type countingEstimator struct {
+	priced []string
+}
+
+func (c *countingEstimator) EstimatePlan(parcels []PackedParcel) (float32, error) {
+	c.priced = append(c.priced, summary(parcels))
+	return float32(len(parcels)), nil
+}
+
+func TestPlanPricesDistinctCandidates(t *testing.T) {
+	parcels := []*store.Parcel{
+		&store.Parcel{Carrier: store.CarriersUsps, ParcelID: "medium", ParcelDimensions: store.Dimensions{Length: "7", Width: "7", Height: "6", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 294}},
+		&store.Parcel{Carrier: store.CarriersUsps, ParcelID: "large", ParcelDimensions: store.Dimensions{Length: "14", Width: "8", Height: "6", DistanceUnit: "in", Weight: "1", MassUnit: "lb", Volume: 672}},
+	}
+	items := []*store.CartItem{
+		&store.CartItem{ItemID: "006", SizeID: "006-OS", Quantity: 2, ShippingDimensions: store.Dimensions{Length: "6", Width: "6", Height: "5", DistanceUnit: "in", Weight: "2", MassUnit: "lb", Volume: 180}},
+	}
+
+	// greedy & "only large" plans pack 1 large box; "max medium" & "only medium" plans pack 2 medium boxes
+	est := &countingEstimator{}
+	if _, err := NewPlanner(NewPacker()).Plan(items, parcels, est, time.Now().Add(DefaultExactBudget)); err != nil {
+		t.Errorf("FAIL: %v", err)
+		return
+	}
+	want := []string{"1 x large", "2 x medium"}
+	if len(est.priced) != len(want) {
+		t.Errorf("FAIL - priced: %v; want: %v", est.priced, want)
+		return
+	}
+	for i, s := range est.priced {
+		if s != want[i] {
+			t.Errorf("FAIL - priced: %s; want: %s", s, want[i])
+		}
+	}
+}
+
+func TestTableRateEstimator(t *testing.T) {
+	est := DefaultTableRates
+	var tests = []struct {
+		parcels []PackedParcel
+		want    float32
+		wantErr bool
+	}{
+		{
+			parcels: []PackedParcel{
+				PackedParcel{Package: store.Package{Carrier: store.CarriersUsps}, Weight: 1.5 * units.Pound},
+				PackedParcel{Package: store.Package{Carrier: store.CarriersUsps, Template: "USPS_SmallFlatRateBox"}, Weight: 4.0 * units.Pound},
+			},
+			want: 20.90,
+		},
+		{ // DIM weight billed
+			parcels: []PackedParcel{
+				PackedParcel{Package: store.Package{Carrier: store.CarriersUsps}, Weight: 4.0 * units.Pound, DimWeight: 12.0 * units.Pound},
+			},
+			want: 35.00,
+		},
+		{ // each carrier has default tiers
+			parcels: []PackedParcel{
+				PackedParcel{Package: store.Package{Carrier: "ups"}, Weight: 1.5 * units.Pound},
+				PackedParcel{Package: store.Package{Carrier: "fedex"}, Weight: 1.5 * units.Pound},
+				PackedParcel{Package: store.Package{Carrier: "dhl_express"}, Weight: 1.5 * units.Pound},
+			},
+			want: 78.80,
+		},
+		{ // over heaviest tier
+			parcels: []PackedParcel{
+				PackedParcel{Package: store.Package{Carrier: store.CarriersUsps}, Weight: 75.0 * units.Pound},
+			},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		cost, err := est.EstimatePlan(test.parcels)
+		if (err != nil) != test.wantErr {
+			t.Errorf("FAIL: %v; want err: %v", err, test.wantErr)
+			continue
+		}
+		if abs(cost-test.want) > 0.001 {
+			t.Errorf("FAIL - cost: %f; want: %f", cost, test.want)
+		}
+	}
+}
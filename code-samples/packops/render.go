@@ -0,0 +1,139 @@
+package packops
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+)
+
+// pixels per inch in SVG diagrams
+const svgScale = float32(20.0)
+
+// margin between SVG diagram panels in pixels
+const svgMargin = float32(20.0)
+
+// Scene represents a simple 3D scene of a Shipment's packed Packages, for use by 3D viewers.
+// Dimensions are in inches.
+type Scene struct {
+	DistanceUnit string        `json:"distance_unit"`
+	Parcels      []SceneParcel `json:"parcels"`
+}
+
+// SceneParcel represents a Package in a Scene, and the units packed in it.
+type SceneParcel struct {
+	Index    int        `json:"index"`
+	ParcelID string     `json:"parcel_id"`
+	Length   float32    `json:"length"`
+	Width    float32    `json:"width"`
+	Height   float32    `json:"height"`
+	Units    []SceneBox `json:"units"`
+}
+
+// SceneBox represents a packed unit in a Scene. X, Y, & Z are the coordinates of the unit's
+// origin corner relative to the parcel's inner corner.
+type SceneBox struct {
+	ItemID      string  `json:"item_id"`
+	Name        string  `json:"name"`
+	Orientation string  `json:"orientation"`
+	X           float32 `json:"x"`
+	Y           float32 `json:"y"`
+	Z           float32 `json:"z"`
+	Length      float32 `json:"length"`
+	Width       float32 `json:"width"`
+	Height      float32 `json:"height"`
+	Color       string  `json:"color"`
+}
+
+// RenderScene returns the 3D scene of the Packages' placements.
+func RenderScene(pkgs []store.Package) (Scene, error) {
+	scene := Scene{DistanceUnit: "in", Parcels: []SceneParcel{}}
+	for i, pkg := range pkgs {
+		l, w, h, err := packageDimsIn(pkg)
+		if err != nil {
+			return Scene{}, err
+		}
+		sp := SceneParcel{Index: i, ParcelID: pkg.ParcelID, Length: l, Width: w, Height: h, Units: []SceneBox{}}
+		for _, pl := range pkg.Placements {
+			sp.Units = append(sp.Units, SceneBox{
+				ItemID:      pl.ItemID,
+				Name:        pl.Name,
+				Orientation: pl.Orientation,
+				X:           pl.X,
+				Y:           pl.Y,
+				Z:           pl.Z,
+				Length:      pl.Length,
+				Width:       pl.Width,
+				Height:      pl.Height,
+				Color:       itemColor(pl.ItemID),
+			})
+		}
+		scene.Parcels = append(scene.Parcels, sp)
+	}
+	return scene, nil
+}
+
+// RenderSVG returns an SVG diagram of each layer of the Package's placements, viewed from above.
+// Each layer contains the units placed at the same height, and layers are drawn left to right, bottom to top.
+func RenderSVG(pkg store.Package) (string, error) {
+	l, w, _, err := packageDimsIn(pkg)
+	if err != nil {
+		return "", err
+	}
+
+	// group placements by height of unit's base
+	layers := map[float32][]store.Placement{}
+	zs := []float32{}
+	for _, pl := range pkg.Placements {
+		if _, ok := layers[pl.Z]; !ok {
+			zs = append(zs, pl.Z)
+		}
+		layers[pl.Z] = append(layers[pl.Z], pl)
+	}
+	sort.Slice(zs, func(i, j int) bool { return zs[i] < zs[j] })
+
+	panelW, panelH := l*svgScale, w*svgScale
+	width := svgMargin + float32(len(zs))*(panelW+svgMargin)
+	height := panelH + 3*svgMargin
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" font-family="sans-serif" font-size="10">`+"\n", width, height)
+	fmt.Fprintf(b, `<title>%s</title>`+"\n", escape(pkg.ParcelID))
+	for i, z := range zs {
+		x0 := svgMargin + float32(i)*(panelW+svgMargin)
+		y0 := 2 * svgMargin
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f">layer %d: z = %.2f in</text>`+"\n", x0, y0-6, i+1, z)
+		fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="none" stroke="black"/>`+"\n", x0, y0, panelW, panelH)
+		for _, pl := range layers[z] {
+			fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" stroke="black"/>`+"\n",
+				x0+pl.X*svgScale, y0+pl.Y*svgScale, pl.Length*svgScale, pl.Width*svgScale, itemColor(pl.ItemID))
+			fmt.Fprintf(b, `<text x="%.1f" y="%.1f">%s %s</text>`+"\n",
+				x0+pl.X*svgScale+2, y0+pl.Y*svgScale+12, escape(pl.ItemID), pl.Orientation)
+		}
+	}
+	b.WriteString("</svg>\n")
+	return b.String(), nil
+}
+
+// packageDimsIn returns the length, width, & height of the Package in inches.
+func packageDimsIn(pkg store.Package) (float32, float32, float32, error) {
+	l, w, h, err := measure(pkg.Dimensions)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return l.Inches(), w.Inches(), h.Inches(), nil
+}
+
+// itemColor returns a fill color derived from the ItemID, so units of the same item share a color.
+func itemColor(itemID string) string {
+	h := fnv.New32a()
+	h.Write([]byte(itemID))
+	return fmt.Sprintf("hsl(%d, 60%%, 75%%)", h.Sum32()%360)
+}
+
+// escape escapes XML special characters.
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(s)
+}
@@ -0,0 +1,59 @@
+package packops
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+)
+
+var testRenderPkg = store.Package{
+	Carrier:    "usps",
+	ParcelID:   "usps_squarebox",
+	Dimensions: store.Dimensions{Length: "6.0", Width: "6.0", Height: "6.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb"},
+	Placements: []store.Placement{
+		store.Placement{ItemID: "002-OS", Orientation: "LWH", X: 0, Y: 0, Z: 0, Length: 5, Width: 5, Height: 2, DistanceUnit: "in"},
+		store.Placement{ItemID: "003-OS", Orientation: "LWH", X: 0, Y: 0, Z: 2, Length: 3, Width: 3, Height: 2, DistanceUnit: "in"},
+		store.Placement{ItemID: "<003>", Orientation: "WLH", X: 3, Y: 0, Z: 2, Length: 2, Width: 3, Height: 2, DistanceUnit: "in"},
+	},
+}
+
+func TestRenderSVG(t *testing.T) {
+	svg, err := RenderSVG(testRenderPkg)
+	if err != nil {
+		t.Errorf("FAIL: %v", err)
+		return
+	}
+	if err := xml.Unmarshal([]byte(svg), new(interface{})); err != nil {
+		t.Errorf("FAIL - invalid xml: %v", err)
+	}
+	if n := strings.Count(svg, "layer "); n != 2 {
+		t.Errorf("FAIL - layers: %d; want: 2", n)
+	}
+	if n := strings.Count(svg, "<rect"); n != 5 {
+		t.Errorf("FAIL - rects: %d; want: 5", n)
+	}
+	if !strings.Contains(svg, "&lt;003&gt;") {
+		t.Errorf("FAIL - item id not escaped")
+	}
+}
+
+func TestRenderScene(t *testing.T) {
+	scene, err := RenderScene([]store.Package{testRenderPkg, testRenderPkg})
+	if err != nil {
+		t.Errorf("FAIL: %v", err)
+		return
+	}
+	if len(scene.Parcels) != 2 || scene.Parcels[1].Index != 1 {
+		t.Errorf("FAIL - parcels: %+v", scene.Parcels)
+		return
+	}
+	p := scene.Parcels[0]
+	if abs(p.Length-6.0) > epsilon || abs(p.Height-6.0) > epsilon || len(p.Units) != 3 {
+		t.Errorf("FAIL - parcel: %+v", p)
+	}
+	if p.Units[1].Color == "" || p.Units[1].Color != itemColor("003-OS") || p.Units[1].Z != 2 {
+		t.Errorf("FAIL - unit: %+v", p.Units[1])
+	}
+}
@@ -0,0 +1,87 @@
+package packops
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+)
+
+// StockAlert represents a Parcel whose on-hand count has fallen below its reorder threshold.
+type StockAlert struct {
+	Carrier      string
+	ParcelID     string
+	Name         string
+	OnHand       int
+	ReorderPoint int
+}
+
+func (a StockAlert) String() string {
+	return fmt.Sprintf("%s %s (%s): %d on hand; reorder point %d", a.Carrier, a.ParcelID, a.Name, a.OnHand, a.ReorderPoint)
+}
+
+// inStock returns true if the Parcel's stock is not tracked, or if any units remain
+// after the units already used for the order.
+func inStock(p *store.Parcel, used map[string]int) bool {
+	return !p.TrackStock || p.OnHand-used[p.ParcelID] > 0
+}
+
+// StockUsed returns the number of Packages using each ParcelID.
+// Packages shipped in their own container are ignored.
+func StockUsed(pkgs []store.Package) map[string]int {
+	used := make(map[string]int)
+	for _, pkg := range pkgs {
+		if pkg.ParcelID == ParcelIDOwnContainer {
+			continue
+		}
+		used[pkg.ParcelID]++
+	}
+	return used
+}
+
+// Reorder returns an alert and true if the tracked Parcel's on-hand count is below its reorder threshold.
+func Reorder(p *store.Parcel) (StockAlert, bool) {
+	if !p.TrackStock || p.OnHand >= p.ReorderPoint {
+		return StockAlert{}, false
+	}
+	alert := StockAlert{Carrier: p.Carrier, ParcelID: p.ParcelID, Name: p.Name, OnHand: p.OnHand, ReorderPoint: p.ReorderPoint}
+	log.Printf("Reorder: %s", alert)
+	return alert, true
+}
+
+// UseStock decrements the on-hand count of each tracked Parcel by the number of Packages using it,
+// and returns an alert for each Parcel whose count falls below its reorder threshold.
+// The caller is responsible for storing the updated Parcels; callers sharing the stored counts
+// should decrement them atomically by StockUsed instead, and check the updated Parcels with Reorder.
+// Parcels without stock tracking and Packages shipped in their own container are ignored.
+func UseStock(parcels []*store.Parcel, pkgs []store.Package) []StockAlert {
+	alerts := []StockAlert{}
+	byID := make(map[string]*store.Parcel)
+	for _, p := range parcels {
+		byID[p.ParcelID] = p
+	}
+
+	used := StockUsed(pkgs)
+	for _, p := range parcels {
+		n := used[p.ParcelID]
+		if !p.TrackStock || n == 0 {
+			continue
+		}
+		p.OnHand -= n
+		if p.OnHand < 0 {
+			log.Printf("UseStock - %s: on hand count negative: %d", p.ParcelID, p.OnHand)
+			p.OnHand = 0
+		}
+		if alert, ok := Reorder(p); ok {
+			alerts = append(alerts, alert)
+		}
+	}
+
+	for id := range used {
+		if byID[id] == nil {
+			log.Printf("UseStock - %s: parcel not in catalog", id)
+		}
+	}
+
+	return alerts
+}
@@ -0,0 +1,107 @@
+package packops
+
+import (
+	"testing"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+)
+
+func TestPackStock(t *testing.T) {
+	medium := &store.Parcel{
+		Carrier:          store.CarriersUsps,
+		ParcelID:         "medium",
+		ParcelDimensions: store.Dimensions{Length: "7", Width: "7", Height: "6", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 294},
+		TrackStock:       true,
+	}
+	large := &store.Parcel{
+		Carrier:          store.CarriersUsps,
+		ParcelID:         "large",
+		ParcelDimensions: store.Dimensions{Length: "14", Width: "8", Height: "6", DistanceUnit: "in", Weight: "1", MassUnit: "lb", Volume: 672},
+	}
+	items := []*store.CartItem{
+		&store.CartItem{
+			ItemID:             "006",
+			SizeID:             "006-OS",
+			Quantity:           1,
+			ShippingDimensions: store.Dimensions{Length: "6", Width: "6", Height: "5", DistanceUnit: "in", Weight: "2", MassUnit: "lb", Volume: 180},
+		},
+		&store.CartItem{
+			ItemID:             "007",
+			SizeID:             "007-OS",
+			Quantity:           1,
+			ShippingDimensions: store.Dimensions{Length: "6", Width: "6", Height: "5", DistanceUnit: "in", Weight: "2", MassUnit: "lb", Volume: 180},
+			Handling:           store.Handling{ShipAlone: true},
+		},
+	}
+
+	var tests = []struct {
+		onHand      int
+		wantParcels []string
+	}{
+		{onHand: 2, wantParcels: []string{"medium", "medium"}},
+		{onHand: 1, wantParcels: []string{"medium", "large"}}, // ship-alone unit uses the last medium box
+		{onHand: 0, wantParcels: []string{"large", "large"}},
+	}
+	for _, test := range tests {
+		medium.OnHand = test.onHand
+		packed, err := NewPacker().Pack(items, []*store.Parcel{medium, large})
+		if err != nil {
+			t.Errorf("FAIL: %v", err)
+			continue
+		}
+		if len(packed) != len(test.wantParcels) {
+			t.Errorf("FAIL - on hand %d: parcels: %d; want: %d", test.onHand, len(packed), len(test.wantParcels))
+			continue
+		}
+		for i, p := range packed {
+			if p.Package.ParcelID != test.wantParcels[i] {
+				t.Errorf("FAIL - on hand %d: parcel %d: %s; want: %s", test.onHand, i, p.Package.ParcelID, test.wantParcels[i])
+			}
+		}
+	}
+}
+
+func TestUseStock(t *testing.T) {
+	parcels := []*store.Parcel{
+		&store.Parcel{Carrier: store.CarriersUsps, ParcelID: "medium", TrackStock: true, OnHand: 5, ReorderPoint: 4},
+		&store.Parcel{Carrier: store.CarriersUsps, ParcelID: "large", TrackStock: true, OnHand: 10, ReorderPoint: 2},
+		&store.Parcel{Carrier: store.CarriersUsps, ParcelID: "mailer"},
+	}
+	pkgs := []store.Package{
+		store.Package{ParcelID: "medium"},
+		store.Package{ParcelID: "medium"},
+		store.Package{ParcelID: "large"},
+		store.Package{ParcelID: "mailer"},
+		store.Package{ParcelID: ParcelIDOwnContainer},
+	}
+	alerts := UseStock(parcels, pkgs)
+	if parcels[0].OnHand != 3 || parcels[1].OnHand != 9 || parcels[2].OnHand != 0 {
+		t.Errorf("FAIL - on hand: %d, %d, %d; want: 3, 9, 0", parcels[0].OnHand, parcels[1].OnHand, parcels[2].OnHand)
+	}
+	if len(alerts) != 1 || alerts[0].ParcelID != "medium" {
+		t.Errorf("FAIL - alerts: %v; want: [medium]", alerts)
+	}
+
+	used := StockUsed(pkgs)
+	if len(used) != 3 || used["medium"] != 2 || used["large"] != 1 || used["mailer"] != 1 {
+		t.Errorf("FAIL - used: %v; want: medium 2, large 1, mailer 1", used)
+	}
+}
+
+func TestReorder(t *testing.T) {
+	var tests = []struct {
+		parcel *store.Parcel
+		want   bool
+	}{
+		{parcel: &store.Parcel{ParcelID: "medium", TrackStock: true, OnHand: 3, ReorderPoint: 4}, want: true},
+		{parcel: &store.Parcel{ParcelID: "medium", TrackStock: true, OnHand: -1, ReorderPoint: 4}, want: true}, // oversold
+		{parcel: &store.Parcel{ParcelID: "medium", TrackStock: true, OnHand: 4, ReorderPoint: 4}, want: false},
+		{parcel: &store.Parcel{ParcelID: "mailer", OnHand: 0, ReorderPoint: 4}, want: false}, // not tracked
+	}
+	for _, test := range tests {
+		alert, ok := Reorder(test.parcel)
+		if ok != test.want || (ok && alert.OnHand != test.parcel.OnHand) {
+			t.Errorf("FAIL - %+v: %v, %v; want: %v", test.parcel, alert, ok, test.want)
+		}
+	}
+}
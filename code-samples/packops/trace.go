@@ -0,0 +1,89 @@
+package packops
+
+import (
+	"fmt"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+)
+
+// Packing decision reasons. Rejected candidate Parcels are recorded with the reason they were rejected.
+const (
+	TraceSelected           = "SELECTED"
+	TraceOwnContainer       = "OWN_CONTAINER"
+	TraceVolumeExceeded     = "VOLUME_EXCEEDED"     // order volume exceeds the parcel's usable volume
+	TraceDimensionsExceeded = "DIMENSIONS_EXCEEDED" // largest unit does not fit the parcel's usable dimensions
+	TraceWeightExceeded     = "WEIGHT_EXCEEDED"     // parcel can not carry the heaviest unit
+	TraceUnitsLeft          = "UNITS_LEFT"          // filled parcel left units unpacked; a parcel fitting more units was selected
+	TraceBillableWeight     = "BILLABLE_WEIGHT"     // parcel fits every unit at a higher billable weight than the selected parcel
+	TraceNoFit              = "NO_FIT"              // no unit fits the parcel
+	TraceOutOfStock         = "OUT_OF_STOCK"        // no units of the parcel on hand
+)
+
+// parcelTrace records each candidate Parcel considered by getParcelForVolume, and
+// the filled candidates' remaining units, until the selected Parcel is known.
+type parcelTrace struct {
+	decisions []store.PackingDecision
+	filled    map[int]PackedParcel // filled candidates by index of decision
+	rem       map[int]int          // remaining units of filled candidates by index of decision
+}
+
+func newParcelTrace() *parcelTrace {
+	return &parcelTrace{
+		decisions: []store.PackingDecision{},
+		filled:    make(map[int]PackedParcel),
+		rem:       make(map[int]int),
+	}
+}
+
+// reject records the Parcel as rejected before it was filled.
+func (t *parcelTrace) reject(p *store.Parcel, reason, detail string) {
+	t.decisions = append(t.decisions, store.PackingDecision{ParcelID: p.ParcelID, Reason: reason, Detail: detail})
+}
+
+// fill records the filled candidate and the number of units that did not fit.
+func (t *parcelTrace) fill(c PackedParcel, rem int) {
+	i := len(t.decisions)
+	t.decisions = append(t.decisions, store.PackingDecision{ParcelID: c.Parcel.ParcelID})
+	t.filled[i] = c
+	t.rem[i] = rem
+}
+
+// result returns the recorded decisions with the selected Parcel marked, and the reason
+// each other filled candidate was rejected.
+func (t *parcelTrace) result(selected PackedParcel) []store.PackingDecision {
+	decisions := []store.PackingDecision{}
+	for i, d := range t.decisions {
+		c, ok := t.filled[i]
+		switch {
+		case !ok:
+		case selected.Parcel != nil && c.Parcel == selected.Parcel:
+			d.Selected = true
+			d.Reason = TraceSelected
+			d.Detail = fmt.Sprintf("billable weight %.2f lb", c.BillableWeight().Pounds())
+			if t.rem[i] > 0 {
+				d.Detail += fmt.Sprintf("; %d units left for next parcel", t.rem[i])
+			}
+		case t.rem[i] > 0:
+			d.Reason = TraceUnitsLeft
+			d.Detail = fmt.Sprintf("%d units do not fit", t.rem[i])
+		default:
+			d.Reason = TraceBillableWeight
+			d.Detail = fmt.Sprintf("billable weight %.2f lb; selected %.2f lb", c.BillableWeight().Pounds(), selected.BillableWeight().Pounds())
+		}
+		decisions = append(decisions, d)
+	}
+	return decisions
+}
+
+// Trace returns the packing decisions of each PackedParcel in order, with the index of the
+// Package each decision was made for, for storage on the store.Shipment.
+func Trace(parcels []PackedParcel) []store.PackingDecision {
+	trace := []store.PackingDecision{}
+	for i, p := range parcels {
+		for _, d := range p.Trace {
+			d.ParcelIndex = i
+			trace = append(trace, d)
+		}
+	}
+	return trace
+}
@@ -0,0 +1,85 @@
+package packops
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+func TestGetParcelForVolumeTrace(t *testing.T) {
+	parcels := []*store.Parcel{
+		&store.Parcel{
+			Carrier:          store.CarriersUsps,
+			ParcelID:         "small",
+			ParcelDimensions: store.Dimensions{Length: "4.0", Width: "4.0", Height: "4.0", DistanceUnit: "in", Weight: "0.25", MassUnit: "lb", Volume: 64.0},
+		},
+		&store.Parcel{
+			Carrier:          store.CarriersUsps,
+			ParcelID:         "medium",
+			ParcelDimensions: store.Dimensions{Length: "6.0", Width: "6.0", Height: "6.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 216.0},
+		},
+		&store.Parcel{
+			Carrier:          store.CarriersUsps,
+			ParcelID:         "light",
+			ParcelDimensions: store.Dimensions{Length: "10.0", Width: "10.0", Height: "10.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 1000.0},
+			MaxWeightLb:      1.0,
+		},
+		&store.Parcel{
+			Carrier:          store.CarriersUsps,
+			ParcelID:         "large",
+			ParcelDimensions: store.Dimensions{Length: "12.0", Width: "12.0", Height: "8.0", DistanceUnit: "in", Weight: "2.0", MassUnit: "lb", Volume: 1152.0},
+		},
+	}
+	items := []PkgItem{
+		PkgItem{ItemID: "002-OS", Length: 5.0 * units.Inch, Width: 5.0 * units.Inch, Height: 2.0 * units.Inch, Volume: 50.0 * units.CubicInch, Weight: 1.0 * units.Pound},
+	}
+	g := NewPacker()
+	g.ExactMaxUnits = 0
+	g.Dunnage = store.Dunnage{}
+
+	packed, rem, err := g.getParcelForVolume(parcels, items, nil, time.Now().Add(g.ExactBudget))
+	if err != nil || len(rem) > 0 {
+		t.Errorf("FAIL: %v; remaining: %d", err, len(rem))
+		return
+	}
+	want := []struct {
+		id     string
+		reason string
+	}{
+		{"small", TraceDimensionsExceeded},
+		{"medium", TraceSelected},
+		{"light", TraceWeightExceeded},
+		{"large", TraceBillableWeight},
+	}
+	if len(packed.Trace) != len(want) {
+		t.Errorf("FAIL - trace: %v", packed.Trace)
+		return
+	}
+	for i, d := range packed.Trace {
+		if d.ParcelID != want[i].id || d.Reason != want[i].reason || d.Selected != (want[i].reason == TraceSelected) {
+			t.Errorf("FAIL - decision %d: %+v; want: %s %s", i, d, want[i].id, want[i].reason)
+		}
+	}
+	// weights are reported in lb
+	if d := packed.Trace[2].Detail; d != "heaviest unit 1.00 lb + tare 0.50 lb; max 1.00 lb" {
+		t.Errorf("FAIL - detail: %s", d)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	parcels := []PackedParcel{
+		PackedParcel{Trace: []store.PackingDecision{
+			store.PackingDecision{ParcelID: "small", Reason: TraceVolumeExceeded},
+			store.PackingDecision{ParcelID: "large", Reason: TraceSelected, Selected: true},
+		}},
+		PackedParcel{Trace: []store.PackingDecision{
+			store.PackingDecision{ParcelID: "small", Reason: TraceSelected, Selected: true},
+		}},
+	}
+	trace := Trace(parcels)
+	if len(trace) != 3 || trace[1].ParcelIndex != 0 || trace[2].ParcelIndex != 1 {
+		t.Errorf("FAIL - trace: %+v", trace)
+	}
+}
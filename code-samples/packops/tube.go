@@ -0,0 +1,131 @@
+package packops
+
+import (
+	"log"
+	"math"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+// ShapeCylinder is the Shape of cylindrical Dimensions, described by Length and Diameter.
+// Dimensions with no Shape are rectangular.
+const ShapeCylinder = "cylinder"
+
+// ParcelTypeTube is the Type of mailing tube Parcels. Tube ParcelDimensions are cylindrical.
+const ParcelTypeTube = "tube"
+
+// BoundingBox returns the rectangular Dimensions enclosing the Dimensions.
+// Cylinders are enclosed by a Length x Diameter x Diameter box.
+func BoundingBox(d store.Dimensions) store.Dimensions {
+	if d.Shape != ShapeCylinder {
+		return d
+	}
+	d.Width, d.Height = d.Diameter, d.Diameter
+	return d
+}
+
+// measure returns the length, width, & height of the Dimensions' bounding box.
+func measure(d store.Dimensions) (units.Length, units.Length, units.Length, error) {
+	b := BoundingBox(d)
+	l, err := units.ParseLength(b.Length, b.DistanceUnit)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	w, err := units.ParseLength(b.Width, b.DistanceUnit)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	h, err := units.ParseLength(b.Height, b.DistanceUnit)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return l, w, h, nil
+}
+
+// mass returns the weight of the Dimensions.
+func mass(d store.Dimensions) (units.Mass, error) {
+	return units.ParseMass(d.Weight, d.MassUnit)
+}
+
+// isTube returns true if the Parcel is a mailing tube.
+func isTube(p *store.Parcel) bool {
+	return p.Type == ParcelTypeTube
+}
+
+// rolled returns true if every unit is cylindrical.
+func rolled(items []PkgItem) bool {
+	for _, item := range items {
+		if item.Shape != ShapeCylinder {
+			return false
+		}
+	}
+	return len(items) > 0
+}
+
+// crossSection returns the diameter of the smallest circle enclosing the unit's cross section
+// when it is rotated with Orientation o, with its rotated length along the tube.
+func crossSection(item PkgItem, o Orientation) units.Length {
+	l, w, h := o.Rotate(item)
+	if item.Shape == ShapeCylinder && l == item.Length {
+		// circular cross section
+		return w
+	}
+	return units.Length(math.Sqrt(float64(w*w + h*h)))
+}
+
+// fillTube fills a mailing tube with the Order's items and
+// returns a list of any remaining items, the parcel's packing list, and an error value.
+// Units are placed end to end along the tube's length, and each unit's cross section must fit
+// the tube's diameter less the padding on each side.
+func (g *GreedyPacker) fillTube(items []PkgItem, parcel *store.Parcel) ([]PkgItem, []PkgItem, error) {
+	rem := []PkgItem{}
+	pack := []PkgItem{}
+
+	pL, pD, _, err := measure(parcel.ParcelDimensions)
+	if err != nil {
+		log.Printf("fillTube failed - get parcel dimensions: %v", err)
+		return rem, pack, err
+	}
+	pad := 2 * padding(parcel.Dunnage)
+	tL, tD := pL-pad, pD-pad
+
+	tare, err := tareWeight(parcel, parcel.Dunnage)
+	if err != nil {
+		log.Printf("fillTube failed - get parcel weight: %v", err)
+		return rem, pack, err
+	}
+	maxWt := g.maxWeight(parcel)
+
+	totalWt, length := tare, units.Length(0.0)
+	for _, item := range items {
+		// shortest orientation that fits the tube's diameter
+		ok := false
+		l := units.Length(0.0)
+		for _, o := range Orientations {
+			if !allowed(item, o) {
+				continue
+			}
+			ol, _, _ := o.Rotate(item)
+			if crossSection(item, o) > tD+epsilon {
+				continue
+			}
+			if !ok || ol < l {
+				ok, l = true, ol
+				item.Orientation = o
+			}
+		}
+		if !ok || length+l > tL+epsilon || (maxWt > 0 && totalWt+item.Weight > maxWt) {
+			// insufficient space or weight
+			rem = append(rem, item)
+			continue
+		}
+
+		item.X, item.Y, item.Z = pad/2+length, pad/2, pad/2
+		length += l
+		totalWt += item.Weight
+		pack = append(pack, item)
+	}
+
+	return rem, pack, nil
+}
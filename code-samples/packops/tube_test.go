@@ -0,0 +1,105 @@
+package packops
+
+import (
+	"testing"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+var testTube = &store.Parcel{
+	Carrier:          "usps",
+	ParcelID:         "usps_tube",
+	Name:             "USPS Mailing Tube",
+	Type:             ParcelTypeTube,
+	ParcelDimensions: store.Dimensions{Shape: ShapeCylinder, Length: "26.0", Diameter: "4.0", DistanceUnit: "in", Weight: "0.3", MassUnit: "lb"},
+}
+
+func TestBoundingBox(t *testing.T) {
+	got := BoundingBox(testTube.ParcelDimensions)
+	if got.Length != "26.0" || got.Width != "4.0" || got.Height != "4.0" {
+		t.Errorf("FAIL: %v; want: 26.0 x 4.0 x 4.0", got)
+	}
+	box := store.Dimensions{Length: "6.0", Width: "6.0", Height: "6.0", DistanceUnit: "in"}
+	if got := BoundingBox(box); got != box {
+		t.Errorf("FAIL: %v; want: %v", got, box)
+	}
+}
+
+func TestFillTube(t *testing.T) {
+	poster := PkgItem{ItemID: "poster", Shape: ShapeCylinder, Length: 24.0 * units.Inch, Width: 3.0 * units.Inch, Height: 3.0 * units.Inch, Weight: 0.5 * units.Pound}
+	mat := PkgItem{ItemID: "playmat", Shape: ShapeCylinder, Length: 14.0 * units.Inch, Width: 3.5 * units.Inch, Height: 3.5 * units.Inch, Weight: 1.0 * units.Pound}
+	wide := PkgItem{ItemID: "wide", Shape: ShapeCylinder, Length: 12.0 * units.Inch, Width: 5.0 * units.Inch, Height: 5.0 * units.Inch, Weight: 1.0 * units.Pound}
+	bar := PkgItem{ItemID: "bar", Length: 10.0 * units.Inch, Width: 2.0 * units.Inch, Height: 2.0 * units.Inch, Weight: 0.5 * units.Pound}
+	flat := PkgItem{ItemID: "flat", Length: 10.0 * units.Inch, Width: 4.0 * units.Inch, Height: 2.0 * units.Inch, Weight: 0.5 * units.Pound}
+
+	var tests = []struct {
+		items    []PkgItem
+		wantPack int
+	}{
+		{items: []PkgItem{poster}, wantPack: 1},
+		{items: []PkgItem{poster, poster}, wantPack: 1}, // length limit
+		{items: []PkgItem{mat}, wantPack: 1},
+		{items: []PkgItem{wide}, wantPack: 0},     // diameter limit
+		{items: []PkgItem{bar, bar}, wantPack: 2}, // 2.83 in diagonal
+		{items: []PkgItem{flat}, wantPack: 0},     // 4.47 in diagonal
+		{items: []PkgItem{mat, bar}, wantPack: 2}, // 24 in end to end
+	}
+	g := NewPacker()
+	for i, test := range tests {
+		rem, pack, err := g.fillTube(test.items, testTube)
+		if err != nil {
+			t.Errorf("FAIL - case %d: %v", i, err)
+			continue
+		}
+		if len(pack) != test.wantPack || len(pack)+len(rem) != len(test.items) {
+			t.Errorf("FAIL - case %d: packed: %d; remaining: %d; want: %d", i, len(pack), len(rem), test.wantPack)
+		}
+	}
+}
+
+func TestPackTube(t *testing.T) {
+	box := &store.Parcel{
+		Carrier:          "usps",
+		ParcelID:         "usps_box",
+		Name:             "USPS Box",
+		ParcelDimensions: store.Dimensions{Length: "30.0", Width: "6.0", Height: "6.0", DistanceUnit: "in", Weight: "1.5", MassUnit: "lb"},
+	}
+	var tests = []struct {
+		items      []*store.CartItem
+		wantParcel string
+	}{
+		{ // rolled poster
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "poster",
+					SizeID:             "poster-24",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Shape: ShapeCylinder, Length: "24.0", Diameter: "3.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 216.0},
+				},
+			},
+			wantParcel: "usps_tube",
+		},
+		{ // boxed item
+			items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "bar",
+					SizeID:             "bar-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "10.0", Width: "2.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 40.0},
+				},
+			},
+			wantParcel: "usps_box",
+		},
+	}
+	for i, test := range tests {
+		packed, err := NewPacker().Pack(test.items, []*store.Parcel{testTube, box})
+		if err != nil {
+			t.Errorf("FAIL - case %d: %v", i, err)
+			continue
+		}
+		if len(packed) != 1 || packed[0].Package.ParcelID != test.wantParcel {
+			t.Errorf("FAIL - case %d: %s; want: 1 x %s", i, summary(packed), test.wantParcel)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package packops
+
+import (
+	"log"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+// PackingError is returned by VerifyPacking and Verify if a packing plan breaks a packing invariant.
+// It indicates a defect in the packing algorithm, not in the order; plans failing verification must not
+// be quoted or shipped. Code is the invariant broken, e.g. PLACEMENTS_OVERLAP.
+type PackingError struct {
+	Code string
+}
+
+// Error returns the PackingError's Code.
+func (e *PackingError) Error() string {
+	return e.Code
+}
+
+// VerifyPacking returns a PackingError if the PackedParcel's placements can not be packed:
+// any 2 units overlap (PLACEMENTS_OVERLAP), a unit lies outside the parcel's inner dimensions
+// less the dunnage's padding on each side (DIMENSIONS_EXCEEDED), or the parcel's weight
+// exceeds maxWeight (WEIGHT_EXCEEDED). maxWeight is not checked if 0.
+// Mailers are measured by volume, so units may exceed a mailer's height, and
+// mailers and flat envelopes reserve no padding.
+func VerifyPacking(p PackedParcel, dn store.Dunnage, maxWeight units.Mass) error {
+	if p.Parcel == nil {
+		return &PackingError{"NO_PARCEL_FOUND"}
+	}
+	pL, pW, pH, err := measure(p.Parcel.ParcelDimensions)
+	if err != nil {
+		log.Printf("VerifyPacking failed: %v", err)
+		return err
+	}
+	pad := padding(dn)
+	if isFlexible(p.Parcel) {
+		pad = 0
+	}
+	pL, pW, pH = pL-pad, pW-pad, pH-pad
+
+	placed := []placement{}
+	for _, item := range p.Items {
+		l, w, h := item.Orientation.Rotate(item)
+		pl := placement{item.X, item.Y, item.Z, l, w, h}
+		if pl.X < pad-epsilon || pl.Y < pad-epsilon || pl.Z < pad-epsilon ||
+			pl.X+l > pL+epsilon || pl.Y+w > pW+epsilon ||
+			(p.Parcel.Type != ParcelTypeMailer && pl.Z+h > pH+epsilon) {
+			log.Printf("VerifyPacking - %s: %s outside parcel: %v", p.Parcel.ParcelID, item.ItemID, pl)
+			return &PackingError{"DIMENSIONS_EXCEEDED"}
+		}
+		for i, o := range placed {
+			if pl.overlaps(o) {
+				log.Printf("VerifyPacking - %s: %s overlaps %s", p.Parcel.ParcelID, item.ItemID, p.Items[i].ItemID)
+				return &PackingError{"PLACEMENTS_OVERLAP"}
+			}
+		}
+		placed = append(placed, pl)
+	}
+
+	if maxWeight > 0 && p.Weight > maxWeight+epsilon {
+		log.Printf("VerifyPacking - %s: weight %.2f lb exceeds %.2f lb", p.Parcel.ParcelID, p.Weight.Pounds(), maxWeight.Pounds())
+		return &PackingError{"WEIGHT_EXCEEDED"}
+	}
+
+	return nil
+}
+
+// Verify returns a PackingError if any PackedParcel fails VerifyPacking with the dunnage and weight limits used
+// to pack it, or if the PackedParcels do not contain exactly the units of the items (UNITS_MISMATCH).
+// Other errors are returned if a parcel's dimensions can not be read.
+func (g *GreedyPacker) Verify(items []*store.CartItem, parcels []PackedParcel) error {
+	want := make(map[string]int)
+	for _, item := range items {
+		want[item.SizeID] += item.Quantity
+	}
+
+	got := make(map[string]int)
+	for _, p := range parcels {
+		if p.Parcel == nil {
+			return &PackingError{"NO_PARCEL_FOUND"}
+		}
+		// mailers, flats, & tubes are filled with their own dunnage; own containers have none
+		dn := g.dunnage(p.Parcel)
+		switch {
+		case isFlexible(p.Parcel), isTube(p.Parcel):
+			dn = p.Parcel.Dunnage
+		case p.Parcel.ParcelID == ParcelIDOwnContainer:
+			dn = store.Dunnage{}
+		}
+		if err := VerifyPacking(p, dn, g.maxWeight(p.Parcel)); err != nil {
+			log.Printf("Verify failed: %v", err)
+			return err
+		}
+		for _, item := range p.Items {
+			got[item.ItemID]++
+		}
+	}
+
+	for id, n := range want {
+		if got[id] != n {
+			log.Printf("Verify - %s: packed %d units; ordered %d", id, got[id], n)
+			return &PackingError{"UNITS_MISMATCH"}
+		}
+	}
+	for id, n := range got {
+		if want[id] == 0 {
+			log.Printf("Verify - %s: packed %d units; ordered 0", id, n)
+			return &PackingError{"UNITS_MISMATCH"}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package packops
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+func TestVerifyPacking(t *testing.T) {
+	parcel := &store.Parcel{
+		Carrier:          store.CarriersUsps,
+		ParcelID:         "usps_squarebox",
+		ParcelDimensions: store.Dimensions{Length: "6.0", Width: "6.0", Height: "6.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb"},
+	}
+	unit := PkgItem{ItemID: "003-OS", Length: 3.0 * units.Inch, Width: 3.0 * units.Inch, Height: 2.0 * units.Inch}
+	at := func(x, y, z units.Length) PkgItem {
+		item := unit
+		item.X, item.Y, item.Z = x*units.Inch, y*units.Inch, z*units.Inch
+		return item
+	}
+
+	var tests = []struct {
+		items   []PkgItem
+		dn      store.Dunnage
+		weight  units.Mass
+		wantErr error
+	}{
+		{items: []PkgItem{at(0, 0, 0), at(3, 0, 0), at(0, 3, 0), at(0, 0, 2)}, weight: 2.0, wantErr: nil},
+		{items: []PkgItem{at(0, 0, 0), at(2, 0, 0)}, weight: 2.0, wantErr: fmt.Errorf("PLACEMENTS_OVERLAP")},
+		{items: []PkgItem{at(0, 0, 0), at(3.5, 0, 0)}, weight: 2.0, wantErr: fmt.Errorf("DIMENSIONS_EXCEEDED")},
+		{items: []PkgItem{at(0, 0, 0)}, dn: store.Dunnage{PaddingIn: 0.5}, weight: 2.0, wantErr: fmt.Errorf("DIMENSIONS_EXCEEDED")}, // inside padding
+		{items: []PkgItem{at(0.5, 0.5, 0.5)}, dn: store.Dunnage{PaddingIn: 0.5}, weight: 2.0, wantErr: nil},
+		{items: []PkgItem{at(0, 0, 0)}, weight: 25.0, wantErr: fmt.Errorf("WEIGHT_EXCEEDED")},
+	}
+	for i, test := range tests {
+		p := PackedParcel{Parcel: parcel, Items: test.items, Weight: test.weight * units.Pound}
+		err := VerifyPacking(p, test.dn, 20.0*units.Pound)
+		if fmt.Sprint(err) != fmt.Sprint(test.wantErr) {
+			t.Errorf("FAIL - case %d: %v; want: %v", i, err, test.wantErr)
+		}
+		var perr *PackingError
+		if err != nil && !errors.As(err, &perr) {
+			t.Errorf("FAIL - case %d: %T; want: *PackingError", i, err)
+		}
+	}
+}
+
+func TestVerifyUnits(t *testing.T) {
+	parcels := []*store.Parcel{
+		&store.Parcel{
+			Carrier:          store.CarriersUsps,
+			ParcelID:         "usps_mediumbox",
+			ParcelDimensions: store.Dimensions{Length: "12.0", Width: "12.0", Height: "8.0", DistanceUnit: "in", Weight: "1.0", MassUnit: "lb", Volume: 1152.0},
+		},
+	}
+	items := []*store.CartItem{
+		&store.CartItem{
+			ItemID:             "003",
+			SizeID:             "003-OS",
+			Quantity:           3,
+			ShippingDimensions: store.Dimensions{Length: "3.0", Width: "3.0", Height: "2.0", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 18.00},
+		},
+	}
+	g := NewPacker()
+	packed, err := g.Pack(items, parcels)
+	if err != nil {
+		t.Errorf("FAIL: %v", err)
+		return
+	}
+	if err := g.Verify(items, packed); err != nil {
+		t.Errorf("FAIL - verify: %v", err)
+	}
+
+	// missing unit
+	packed[0].Items = packed[0].Items[1:]
+	if err := g.Verify(items, packed); fmt.Sprint(err) != "UNITS_MISMATCH" {
+		t.Errorf("FAIL - missing unit: %v; want: UNITS_MISMATCH", err)
+	}
+}
+
+// randomCart returns a cart of 1 - 4 items with random dimensions, weights, quantities of 1 - maxQty,
+// & handling attributes.
+func randomCart(r *rand.Rand, maxQty int) []*store.CartItem {
+	items := []*store.CartItem{}
+	n := 1 + r.Intn(4)
+	for i := 0; i < n; i++ {
+		l, w, h := 1+r.Float32()*9, 1+r.Float32()*9, 0.5+r.Float32()*5
+		items = append(items, &store.CartItem{
+			ItemID:   fmt.Sprintf("%03d", i),
+			SizeID:   fmt.Sprintf("%03d-OS", i),
+			Quantity: 1 + r.Intn(maxQty),
+			ShippingDimensions: store.Dimensions{
+				Length:       fmt.Sprintf("%.1f", l),
+				Width:        fmt.Sprintf("%.1f", w),
+				Height:       fmt.Sprintf("%.1f", h),
+				DistanceUnit: "in",
+				Weight:       fmt.Sprintf("%.1f", 0.1+r.Float32()*10),
+				MassUnit:     "lb",
+				Volume:       l * w * h,
+			},
+			Handling: store.Handling{
+				Fragile:    r.Intn(8) == 0,
+				ThisSideUp: r.Intn(4) == 0,
+				NoStack:    r.Intn(8) == 0,
+				SoftPack:   r.Intn(4) == 0,
+			},
+		})
+	}
+	return items
+}
+
+// TestVerifyRandomCarts packs randomly generated carts and verifies every plan.
+func TestVerifyRandomCarts(t *testing.T) {
+	parcels := []*store.Parcel{
+		&store.Parcel{
+			Carrier:          store.CarriersUsps,
+			ParcelID:         "usps_mailer",
+			Type:             ParcelTypeMailer,
+			ParcelDimensions: store.Dimensions{Length: "14.0", Width: "10.0", Height: "2.0", DistanceUnit: "in", Weight: "0.1", MassUnit: "lb", Volume: 280.0},
+		},
+		&store.Parcel{
+			Carrier:          store.CarriersUsps,
+			ParcelID:         "usps_mediumbox",
+			ParcelDimensions: store.Dimensions{Length: "12.0", Width: "12.0", Height: "8.0", DistanceUnit: "in", Weight: "1.0", MassUnit: "lb", Volume: 1152.0},
+			Dunnage:          store.Dunnage{PaddingIn: 0.5, VoidFillPct: 0.05},
+		},
+		&store.Parcel{
+			Carrier:          store.CarriersUsps,
+			ParcelID:         "usps_largebox",
+			ParcelDimensions: store.Dimensions{Length: "24.0", Width: "18.0", Height: "12.0", DistanceUnit: "in", Weight: "2.0", MassUnit: "lb", Volume: 5184.0},
+			MaxWeightLb:      50.0,
+		},
+	}
+	r := rand.New(rand.NewSource(1))
+	for _, strategy := range []Strategy{StrategyGuillotine, StrategyExtremePoint} {
+		g := NewPacker()
+		g.Strategy = strategy
+		grid := 0 // carts with runs long enough for grid layers
+		for i := 0; i < 200; i++ {
+			// quantities reach past GridMinUnits so runs are placed as grid layers
+			items := randomCart(r, g.GridMinUnits+4)
+			for _, item := range items {
+				if item.Quantity >= g.GridMinUnits {
+					grid++
+					break
+				}
+			}
+			packed, err := g.Pack(items, parcels)
+			if err != nil {
+				if err.Error() != "NO_PARCEL_FOUND" {
+					t.Errorf("FAIL - pack: %v", err)
+				}
+				// unit does not fit any parcel
+				continue
+			}
+			if err := g.Verify(items, packed); err != nil {
+				t.Errorf("FAIL - %s cart %d: %v", strategy, i, err)
+			}
+		}
+		if grid == 0 {
+			t.Errorf("FAIL - %s: no carts reach GridMinUnits", strategy)
+		}
+	}
+}
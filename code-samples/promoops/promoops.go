@@ -0,0 +1,207 @@
+package promoops
+
+/* promoops applies shipping promotions to the rates quoted for an order, e.g. "free Ground over $75" or
+   "$5 off Priority for members". Promotions are defined as store.ShippingRule objects: each rule has a set
+   of conditions on the order's subtotal, items, destination, and the customer's membership, and an action
+   applied to the matching rates when every condition is met:
+
+   - discount: the rule's Amount is taken off the price of each matching rate (to a minimum of $0.00)
+   - free: each matching rate is charged $0.00
+   - hide: each matching rate is removed
+   - add: the rule's Rate is added, if no rate with its service level token was quoted. Added rates are
+     marked Added: they have no carrier rate or packing plan, and are fulfilled without a shipping label
+     (e.g. local pickup)
+
+   Rules list items by product ItemID (store.CartItem.ItemID), so a rule applies to every size of the
+   product; the SizeIDs the packing engine keys on are not matched.
+
+   Rules are applied in ascending order of Priority, so discounts stack and a later rule acts on the rates
+   left by earlier rules. The charged price is stored in RateSummary.Price & PriceFloat; the quoted price is
+   kept in OriginalPrice & OriginalPriceFloat, and the names of the applied rules in Promotion.
+   Apply keeps the order of the quoted rates; SortByPrice re-sorts them by charged price.
+*/
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+)
+
+// rule actions
+const (
+	ActionDiscount = "discount"
+	ActionFree     = "free"
+	ActionHide     = "hide"
+	ActionAdd      = "add"
+)
+
+// Cart represents the order info shipping rules are evaluated against.
+type Cart struct {
+	Subtotal float32 // USD
+	Items    []*store.CartItem
+	Address  store.Address // shipping address
+	Member   bool          // customer is a site member
+}
+
+// Apply returns a copy of the rates with the rules applied in order of priority.
+// Rules with an unknown action are skipped. The rates are not modified.
+func Apply(rules []*store.ShippingRule, cart Cart, rates []*store.RateSummary) []*store.RateSummary {
+	applied := []*store.RateSummary{}
+	for _, r := range rates {
+		rate := *r
+		if rate.OriginalPrice == "" {
+			rate.OriginalPrice, rate.OriginalPriceFloat = rate.Price, rate.PriceFloat
+		}
+		applied = append(applied, &rate)
+	}
+
+	ordered := make([]*store.ShippingRule, len(rules))
+	copy(ordered, rules)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Priority < ordered[j].Priority })
+
+	for _, rule := range ordered {
+		if !Matches(rule, cart) {
+			continue
+		}
+		switch rule.Action {
+		case ActionDiscount:
+			for _, rate := range applied {
+				if appliesTo(rule, rate) {
+					charge(rate, rate.PriceFloat-rule.Amount, rule.Name)
+				}
+			}
+		case ActionFree:
+			for _, rate := range applied {
+				if appliesTo(rule, rate) {
+					charge(rate, 0, rule.Name)
+				}
+			}
+		case ActionHide:
+			kept := []*store.RateSummary{}
+			for _, rate := range applied {
+				if !appliesTo(rule, rate) {
+					kept = append(kept, rate)
+				}
+			}
+			applied = kept
+		case ActionAdd:
+			if quoted(applied, rule.Rate.ServiceLevel.Token) {
+				continue
+			}
+			rate := rule.Rate
+			rate.OriginalPrice, rate.OriginalPriceFloat = rate.Price, rate.PriceFloat
+			rate.Promotion = rule.Name
+			rate.Added = true
+			applied = append(applied, &rate)
+		default:
+			log.Printf("Apply - %s: unknown action: %s", rule.RuleID, rule.Action)
+		}
+	}
+	return applied
+}
+
+// SortByPrice sorts the rates by charged price, least to greatest. Rates with equal prices keep their order.
+func SortByPrice(rates []*store.RateSummary) []*store.RateSummary {
+	sort.SliceStable(rates, func(i, j int) bool {
+		return rates[i].PriceFloat < rates[j].PriceFloat
+	})
+	return rates
+}
+
+// Matches returns true if the cart meets every condition of the rule.
+// Empty conditions are met by every cart.
+func Matches(rule *store.ShippingRule, cart Cart) bool {
+	if cart.Subtotal < rule.MinSubtotal {
+		return false
+	}
+	if rule.MembersOnly && !cart.Member {
+		return false
+	}
+	if len(rule.ItemIDs) > 0 && !containsItem(cart.Items, rule.ItemIDs) {
+		return false
+	}
+	if len(rule.Countries) > 0 && !containsFold(rule.Countries, cart.Address.Country) {
+		return false
+	}
+	if len(rule.States) > 0 && !containsFold(rule.States, cart.Address.State) {
+		return false
+	}
+	if len(rule.ZipPrefixes) > 0 {
+		found := false
+		for _, prefix := range rule.ZipPrefixes {
+			if strings.HasPrefix(cart.Address.Zip, prefix) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// appliesTo returns true if the rate's service level is listed in the rule's Services.
+// Services are listed by service level token (e.g. "usps_priority") or carrier token (e.g. "usps");
+// rules with no Services apply to every rate.
+func appliesTo(rule *store.ShippingRule, rate *store.RateSummary) bool {
+	if len(rule.Services) == 0 {
+		return true
+	}
+	token := rate.ServiceLevel.Token
+	for _, s := range rule.Services {
+		if token == s || strings.HasPrefix(token, s+"_") {
+			return true
+		}
+	}
+	return false
+}
+
+// charge sets the charged price of the rate, to a minimum of 0, and records the promotion
+func charge(rate *store.RateSummary, price float32, promotion string) {
+	if price < 0 {
+		price = 0
+	}
+	rate.PriceFloat = price
+	rate.Price = fmt.Sprintf("%.2f", price)
+	if rate.Promotion == "" {
+		rate.Promotion = promotion
+		return
+	}
+	rate.Promotion += ", " + promotion
+}
+
+// quoted returns true if a rate with the service level token is in rates
+func quoted(rates []*store.RateSummary, token string) bool {
+	for _, rate := range rates {
+		if rate.ServiceLevel.Token == token {
+			return true
+		}
+	}
+	return false
+}
+
+// containsItem returns true if the product ItemID of any of the cart items is listed in itemIDs
+func containsItem(items []*store.CartItem, itemIDs []string) bool {
+	for _, item := range items {
+		for _, id := range itemIDs {
+			if item.ItemID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsFold returns true if s is in list, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, l := range list {
+		if strings.EqualFold(l, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package promoops
+
+import (
+	"testing"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+)
+
+var testRules = []*store.ShippingRule{
+	&store.ShippingRule{RuleID: "001", Name: "Free Ground over $75", Priority: 2, Action: ActionFree, MinSubtotal: 75.0, Services: []string{"usps_ground_advantage", "ups_ground"}, Countries: []string{"US"}},
+	&store.ShippingRule{RuleID: "002", Name: "$5 off Priority for members", Priority: 1, Action: ActionDiscount, Amount: 5.0, MembersOnly: true, Services: []string{"usps_priority"}},
+	&store.ShippingRule{RuleID: "003", Name: "No UPS to Hawaii", Priority: 0, Action: ActionHide, States: []string{"HI"}, Services: []string{"ups"}},
+	&store.ShippingRule{RuleID: "004", Name: "Local pickup", Priority: 3, Action: ActionAdd, ZipPrefixes: []string{"900", "902"},
+		Rate: store.RateSummary{Price: "0.00", Currency: "USD", Provider: "ACamoPRJCT", Carrier: "pickup", ServiceLevel: store.ServiceLevel{Name: "Local Pickup", Token: "pickup_local"}}},
+	&store.ShippingRule{RuleID: "005", Name: "Chess set upgrade", Priority: 4, Action: ActionDiscount, Amount: 20.0, ItemIDs: []string{"chess-001"}, Services: []string{"usps_priority"}},
+	&store.ShippingRule{RuleID: "006", Name: "Unknown", Action: "double"},
+}
+
+func testRates() []*store.RateSummary {
+	return []*store.RateSummary{
+		&store.RateSummary{Price: "5.50", PriceFloat: 5.5, Currency: "USD", Provider: "USPS", Carrier: "usps", ServiceLevel: store.ServiceLevel{Token: "usps_ground_advantage"}},
+		&store.RateSummary{Price: "8.00", PriceFloat: 8.0, Currency: "USD", Provider: "USPS", Carrier: "usps", ServiceLevel: store.ServiceLevel{Token: "usps_priority"}},
+		&store.RateSummary{Price: "11.20", PriceFloat: 11.2, Currency: "USD", Provider: "UPS", Carrier: "ups", ServiceLevel: store.ServiceLevel{Token: "ups_ground"}},
+	}
+}
+
+func TestMatchesItems(t *testing.T) {
+	rule := testRules[4] // chess-001
+	var tests = []struct {
+		item *store.CartItem
+		want bool
+	}{
+		{item: &store.CartItem{ItemID: "chess-001", SizeID: "chess-001-OS"}, want: true},
+		{item: &store.CartItem{ItemID: "chess-001", SizeID: "chess-001-L"}, want: true}, // every size
+		{item: &store.CartItem{ItemID: "board-001", SizeID: "chess-001"}, want: false},  // SizeIDs not matched
+	}
+	for _, test := range tests {
+		if got := Matches(rule, Cart{Items: []*store.CartItem{test.item}}); got != test.want {
+			t.Errorf("FAIL - %+v: %v; want: %v", test.item, got, test.want)
+		}
+	}
+}
+
+// charged returns the charged price & promotion of each rate by service level token
+func charged(rates []*store.RateSummary) map[string]string {
+	m := make(map[string]string)
+	for _, r := range rates {
+		m[r.ServiceLevel.Token] = r.Price + " " + r.OriginalPrice + " " + r.Promotion
+	}
+	return m
+}
+
+func TestApply(t *testing.T) {
+	var tests = []struct {
+		name string
+		cart Cart
+		want map[string]string // charged price, original price, & promotion by token
+	}{
+		{name: "no promotions", cart: Cart{Subtotal: 50.0, Address: store.Address{State: "NY", Zip: "10001", Country: "US"}}, want: map[string]string{
+			"usps_ground_advantage": "5.50 5.50 ",
+			"usps_priority":         "8.00 8.00 ",
+			"ups_ground":            "11.20 11.20 ",
+		}},
+		{name: "free ground", cart: Cart{Subtotal: 75.0, Address: store.Address{State: "NY", Zip: "10001", Country: "us"}}, want: map[string]string{
+			"usps_ground_advantage": "0.00 5.50 Free Ground over $75",
+			"usps_priority":         "8.00 8.00 ",
+			"ups_ground":            "0.00 11.20 Free Ground over $75",
+		}},
+		{name: "free ground domestic only", cart: Cart{Subtotal: 100.0, Address: store.Address{Zip: "M5V", Country: "CA"}}, want: map[string]string{
+			"usps_ground_advantage": "5.50 5.50 ",
+			"usps_priority":         "8.00 8.00 ",
+			"ups_ground":            "11.20 11.20 ",
+		}},
+		{name: "member discount & hidden carrier", cart: Cart{Subtotal: 20.0, Member: true, Address: store.Address{State: "HI", Zip: "96813", Country: "US"}}, want: map[string]string{
+			"usps_ground_advantage": "5.50 5.50 ",
+			"usps_priority":         "3.00 8.00 $5 off Priority for members",
+		}},
+		{name: "added rate", cart: Cart{Subtotal: 20.0, Address: store.Address{State: "CA", Zip: "90001", Country: "US"}}, want: map[string]string{
+			"usps_ground_advantage": "5.50 5.50 ",
+			"usps_priority":         "8.00 8.00 ",
+			"ups_ground":            "11.20 11.20 ",
+			"pickup_local":          "0.00 0.00 Local pickup",
+		}},
+		{name: "stacked discounts", cart: Cart{Subtotal: 20.0, Member: true, Items: []*store.CartItem{&store.CartItem{ItemID: "chess-001"}}, Address: store.Address{State: "NY", Zip: "10001", Country: "US"}}, want: map[string]string{
+			"usps_ground_advantage": "5.50 5.50 ",
+			"usps_priority":         "0.00 8.00 $5 off Priority for members, Chess set upgrade",
+			"ups_ground":            "11.20 11.20 ",
+		}},
+	}
+	for _, test := range tests {
+		rates := testRates()
+		got := charged(Apply(testRules, test.cart, rates))
+		if len(got) != len(test.want) {
+			t.Errorf("FAIL - %s: %v; want: %v", test.name, got, test.want)
+			continue
+		}
+		for token, want := range test.want {
+			if got[token] != want {
+				t.Errorf("FAIL - %s %s: %q; want: %q", test.name, token, got[token], want)
+			}
+		}
+		// quoted rates are not modified
+		if rates[1].Price != "8.00" || rates[1].OriginalPrice != "" {
+			t.Errorf("FAIL - %s: rates modified: %+v", test.name, rates[1])
+		}
+	}
+}
+
+func TestApplyOrder(t *testing.T) {
+	// rates keep their order; added rates are appended & marked Added
+	rates := Apply(testRules, Cart{Subtotal: 80.0, Address: store.Address{State: "HI", Zip: "90210", Country: "US"}}, testRates())
+	want := []string{"usps_ground_advantage", "usps_priority", "pickup_local"}
+	if len(rates) != len(want) {
+		t.Errorf("FAIL - rates: %v; want: %v", charged(rates), want)
+		return
+	}
+	for i, rate := range rates {
+		if rate.ServiceLevel.Token != want[i] {
+			t.Errorf("FAIL - rate %d: %s; want: %s", i, rate.ServiceLevel.Token, want[i])
+		}
+		if rate.Added != (want[i] == "pickup_local") {
+			t.Errorf("FAIL - rate %d: added: %v", i, rate.Added)
+		}
+	}
+}
+
+func TestSortByPrice(t *testing.T) {
+	var tests = []struct {
+		name string
+		cart Cart
+		want []string
+	}{
+		{name: "no promotions", cart: Cart{Subtotal: 50.0, Address: store.Address{State: "NY", Zip: "10001", Country: "US"}},
+			want: []string{"usps_ground_advantage", "usps_priority", "ups_ground"}},
+		{name: "free ground", cart: Cart{Subtotal: 75.0, Address: store.Address{State: "NY", Zip: "10001", Country: "US"}},
+			want: []string{"usps_ground_advantage", "ups_ground", "usps_priority"}},
+		{name: "free priority", cart: Cart{Subtotal: 20.0, Member: true, Items: []*store.CartItem{&store.CartItem{ItemID: "chess-001"}}, Address: store.Address{State: "NY", Zip: "10001", Country: "US"}},
+			want: []string{"usps_priority", "usps_ground_advantage", "ups_ground"}},
+	}
+	for _, test := range tests {
+		rates := SortByPrice(Apply(testRules, test.cart, testRates()))
+		if len(rates) != len(test.want) {
+			t.Errorf("FAIL - %s: %v; want: %v", test.name, charged(rates), test.want)
+			continue
+		}
+		for i, rate := range rates {
+			if rate.ServiceLevel.Token != test.want[i] {
+				t.Errorf("FAIL - %s rate %d: %s; want: %s", test.name, i, rate.ServiceLevel.Token, test.want[i])
+			}
+		}
+	}
+}
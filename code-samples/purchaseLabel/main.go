@@ -0,0 +1,375 @@
+package main
+
+/* purchaseLabel purchases the shipping labels for an order's selected rate, and updates the stock of
+   the parcels used to pack the order. Admin staff call it when processing an open order.
+
+   The selected rate is re-rated with the shipping API before purchase, since quoted rates may have
+   expired or been estimated offline (see getShippingMethods). The packages of the selected carrier's
+   packing plan are sent to the shipping API; the plan becomes the shipment's plan for fulfillment.
+
+   Concurrent requests for the same order are serialized by a claim on the shipment: the request sets the
+   shipment's LabelClaim with a conditional write before purchasing, and the labels are only saved while
+   the claim is held. A claim is released if the purchase fails, and expires after labelClaimTimeout.
+
+   After the labels are purchased, the on-hand count of each tracked parcel is decremented atomically in
+   the Parcels table by the number of packages using it (packops.StockUsed). Parcels falling below their
+   reorder point are returned to the caller as stock alerts.
+
+   The store.Shipment fields & dbops functions this function depends on are listed in
+   documentation/store_api_changes.txt.
+*/
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apex/gateway"
+	"github.com/coldbrewcloud/go-shippo"
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/dbops"
+	"github.com/ggarcia209/acamoprjct/service/util/httpops"
+	"github.com/ggarcia209/acamoprjct/service/util/packops"
+	"github.com/ggarcia209/acamoprjct/service/util/rateops"
+	"github.com/ggarcia209/acamoprjct/service/util/shipops"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+	"github.com/ggarcia209/go-aws/go-dynamo/dynamo"
+)
+
+const route = "/admin/orders/purchase_label" // PUT
+
+const failMsg = "Request failed!"
+const successMsg = "Request succeeded!"
+
+// labelClaimTimeout is the time after which an unfinished label purchase may be claimed by another request
+const labelClaimTimeout = 10 * time.Minute
+
+// envarShippingAPI contains the shipping API used to purchase labels
+const envarShippingAPI = "SHIPPING_API"
+
+// shipping APIs
+const (
+	apiShippo   = "shippo"
+	apiEasyPost = "easypost"
+)
+
+// list of tables function makes r/w calls to
+var tables = []dbops.Table{
+	dbops.Table{
+		Name:       dbops.ShipmentsTable(),
+		PrimaryKey: dbops.ShipmentsPK,
+	},
+	dbops.Table{
+		Name:       dbops.ParcelsTable(),
+		PrimaryKey: dbops.ParcelsPK,
+	},
+}
+
+// labelRequest represents the request info submitted from the admin portal
+type labelRequest struct {
+	OrderID      string `json:"order_id"`
+	ServiceToken string `json:"service_token"` // service level token of the customer's selected rate
+}
+
+// labelResponse represents the purchased labels and any parcel stock alerts
+type labelResponse struct {
+	Labels      []store.ShippingLabel `json:"labels"`
+	StockAlerts []string              `json:"stock_alerts"`
+}
+
+// RootHandler handles HTTP request
+func RootHandler(w http.ResponseWriter, r *http.Request) {
+	// DB is used to make DynamoDB API calls
+	DB := dbops.InitDB(tables)
+
+	// verify content-type
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "application/json" {
+		httpops.ErrResponse(w, "Content-Type is not application/json", failMsg, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	// decode JSON object from http request
+	data := labelRequest{}
+	var unmarshalErr *json.UnmarshalTypeError
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&data)
+	if err != nil {
+		if errors.As(err, &unmarshalErr) {
+			httpops.ErrResponse(w, "Bad Request: Wrong type provided for field "+unmarshalErr.Field, failMsg, http.StatusBadRequest)
+		} else {
+			httpops.ErrResponse(w, "Bad Request: "+err.Error(), failMsg, http.StatusBadRequest)
+		}
+		return
+	}
+
+	if data.OrderID == "" || data.ServiceToken == "" {
+		log.Printf("bad request - empty keys")
+		httpops.ErrResponse(w, "Bad Request: empty order keys", failMsg, http.StatusBadRequest)
+		return
+	}
+
+	// get shipment
+	shipment, err := dbops.GetShipment(DB, data.OrderID)
+	if err != nil {
+		log.Printf("RootHandler failed - getShipment: %v", err)
+		httpops.ErrResponse(w, "Internal Server Error: "+err.Error(), failMsg, http.StatusInternalServerError)
+		return
+	}
+	if shipment.OrderID == "" {
+		httpops.ErrResponse(w, "Not Found: no shipment for order "+data.OrderID, failMsg, http.StatusNotFound)
+		return
+	}
+	if len(shipment.Labels) > 0 {
+		httpops.ErrResponse(w, "Conflict: labels already purchased for order "+data.OrderID, failMsg, http.StatusConflict)
+		return
+	}
+
+	// initialize shipping API
+	rp, err := newRateProvider()
+	if err != nil {
+		log.Printf("RootHandler failed - newRateProvider: %v", err)
+		httpops.ErrResponse(w, "Internal Server Error: "+err.Error(), failMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// claim shipment, so concurrent requests do not purchase its labels twice
+	claim := time.Now().UnixNano()
+	err = dbops.ClaimLabelPurchase(DB, data.OrderID, claim, claim-int64(labelClaimTimeout))
+	if err != nil {
+		log.Printf("RootHandler failed - claimLabelPurchase: %v", err)
+		if err.Error() == "LABEL_PURCHASE_CLAIMED" {
+			httpops.ErrResponse(w, "Conflict: labels already purchased or in progress for order "+data.OrderID, failMsg, http.StatusConflict)
+			return
+		}
+		httpops.ErrResponse(w, "Internal Server Error: "+err.Error(), failMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// re-rate & purchase labels
+	labels, plan, err := purchaseLabels(rp, shipment, data.ServiceToken)
+	if err != nil {
+		log.Printf("RootHandler failed - purchaseLabels: %v", err)
+		// no labels purchased - release claim so the request may be retried
+		if rErr := dbops.ReleaseLabelPurchase(DB, data.OrderID, claim); rErr != nil {
+			log.Printf("RootHandler - releaseLabelPurchase: %v", rErr)
+		}
+		code := http.StatusInternalServerError
+		if err.Error() == "NO_RATE_FOUND" || err.Error() == "NO_PLAN_FOUND" || err.Error() == "NO_LABEL_REQUIRED" {
+			code = http.StatusBadRequest
+		}
+		httpops.ErrResponse(w, "Request failed: "+err.Error(), failMsg, code)
+		return
+	}
+
+	// labels are purchased - record them before updating stock, so a failed request is not retried
+	// the claim is kept if the write fails; the labels must be recorded by hand
+	shipment.Labels = labels
+	shipment.LabelClaim = claim
+	shipment.Packages, shipment.PackingTrace, shipment.PlanReason = plan.Packages, plan.PackingTrace, plan.PlanReason
+	err = dbops.PutShipmentLabels(DB, shipment)
+	if err != nil {
+		log.Printf("RootHandler failed - putShipmentLabels: labels %v: %v", labels, err)
+		httpops.ErrResponse(w, "Internal Server Error: "+err.Error(), "SAVE_LABELS_FAIL", http.StatusInternalServerError)
+		return
+	}
+
+	// decrement parcel stock
+	alerts, err := useStock(DB, plan.Packages)
+	if err != nil {
+		log.Printf("RootHandler failed - useStock: %v", err)
+		httpops.ErrResponse(w, "Internal Server Error: labels purchased; stock update failed: "+err.Error(), "UPDATE_STOCK_FAIL", http.StatusInternalServerError)
+		return
+	}
+
+	resp := labelResponse{Labels: labels, StockAlerts: []string{}}
+	for _, a := range alerts {
+		resp.StockAlerts = append(resp.StockAlerts, a.String())
+	}
+	httpops.ErrResponse(w, "Labels purchased: ", resp, http.StatusOK)
+	return
+}
+
+// purchaseLabels re-rates the packages of the selected carrier's packing plan for the service level,
+// and purchases the labels of the new rate. The labels and the plan are returned.
+func purchaseLabels(rp rateops.RateProvider, shipment *store.Shipment, token string) ([]store.ShippingLabel, store.PackingPlan, error) {
+	selected, ok := selectedRate(shipment.Rates, token)
+	if !ok {
+		log.Printf("purchaseLabels failed - %s: rate not quoted", token)
+		return nil, store.PackingPlan{}, fmt.Errorf("NO_RATE_FOUND")
+	}
+	if selected.Added {
+		// rates added by promotions (e.g. local pickup) are fulfilled without a label
+		log.Printf("purchaseLabels failed - %s: rate added by promotion %q", token, selected.Promotion)
+		return nil, store.PackingPlan{}, fmt.Errorf("NO_LABEL_REQUIRED")
+	}
+	plan, ok := carrierPlan(shipment, selected.Carrier)
+	if !ok || len(plan.Packages) == 0 {
+		log.Printf("purchaseLabels failed - %s: no packing plan", selected.Carrier)
+		return nil, store.PackingPlan{}, fmt.Errorf("NO_PLAN_FOUND")
+	}
+
+	// re-rate shipment
+	from, err := rp.CreateAddress(shipment.AddressFrom, false)
+	if err != nil {
+		log.Printf("purchaseLabels failed: %v", err)
+		return nil, plan, err
+	}
+	to, err := rp.CreateAddress(shipment.AddressTo, false)
+	if err != nil {
+		log.Printf("purchaseLabels failed: %v", err)
+		return nil, plan, err
+	}
+	parcels := []rateops.Parcel{}
+	for _, pkg := range plan.Packages {
+		parcel, err := rp.CreateParcel(packedParcel(pkg))
+		if err != nil {
+			log.Printf("purchaseLabels failed: %v", err)
+			return nil, plan, err
+		}
+		parcels = append(parcels, parcel)
+	}
+	rated, err := rp.CreateShipment(from, to, parcels)
+	if err != nil {
+		log.Printf("purchaseLabels failed: %v", err)
+		return nil, plan, err
+	}
+
+	rate, found := rateops.Rate{}, false
+	for _, r := range rated.Rates {
+		if r.ServiceLevel.Token == token {
+			rate, found = r, true
+			break
+		}
+	}
+	if !found {
+		log.Printf("purchaseLabels failed - %s: not available for shipment", token)
+		return nil, plan, fmt.Errorf("RATE_UNAVAILABLE")
+	}
+	if selected.Estimated || rate.PriceFloat != selected.OriginalPriceFloat {
+		log.Printf("purchaseLabels - %s re-rated: quoted $%.2f (estimated: %v); purchased $%s", token, selected.OriginalPriceFloat, selected.Estimated, rate.Price)
+	}
+
+	purchased, err := rp.PurchaseLabel(rate)
+	if err != nil {
+		log.Printf("purchaseLabels failed: %v", err)
+		return nil, plan, err
+	}
+	labels := []store.ShippingLabel{}
+	for _, l := range purchased {
+		labels = append(labels, store.ShippingLabel{LabelID: l.ID, TrackingNumber: l.TrackingNumber, LabelURL: l.LabelURL})
+	}
+	return labels, plan, nil
+}
+
+// selectedRate returns the quoted rate of the service level token
+func selectedRate(rates []store.RateSummary, token string) (store.RateSummary, bool) {
+	for _, rate := range rates {
+		if rate.ServiceLevel.Token == token {
+			return rate, true
+		}
+	}
+	return store.RateSummary{}, false
+}
+
+// carrierPlan returns the packing plan quoted for the carrier
+// shipments quoted before plans were stored by carrier use the shipment's plan
+func carrierPlan(shipment *store.Shipment, carrier string) (store.PackingPlan, bool) {
+	if len(shipment.Plans) == 0 {
+		return store.PackingPlan{Packages: shipment.Packages, PackingTrace: shipment.PackingTrace, PlanReason: shipment.PlanReason}, true
+	}
+	plan, ok := shipment.Plans[carrier]
+	return plan, ok
+}
+
+// packedParcel returns the packops.PackedParcel of a stored Package for re-rating
+func packedParcel(pkg store.Package) packops.PackedParcel {
+	return packops.PackedParcel{
+		Parcel: &store.Parcel{
+			Carrier:          pkg.Carrier,
+			ParcelID:         pkg.ParcelID,
+			Name:             pkg.Name,
+			Template:         pkg.Template,
+			ParcelDimensions: pkg.Dimensions,
+		},
+		Package:   pkg,
+		Weight:    units.Mass(pkg.ActualWeightLb) * units.Pound,
+		DimWeight: units.Mass(pkg.DimWeightLb) * units.Pound,
+	}
+}
+
+// useStock decrements the on-hand count of each tracked parcel used by the packages in the DB,
+// and returns an alert for each parcel below its reorder point
+// counts are decremented atomically, so concurrent purchases do not overwrite each other's counts
+func useStock(DB *dynamo.DbInfo, pkgs []store.Package) ([]packops.StockAlert, error) {
+	alerts := []packops.StockAlert{}
+	used := packops.StockUsed(pkgs)
+	ids := []string{}
+	for id := range used {
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return alerts, nil
+	}
+	sort.Strings(ids)
+
+	parcels, err := dbops.BatchGetParcels(DB, ids)
+	if err != nil {
+		log.Printf("useStock failed: %v", err)
+		return nil, err
+	}
+
+	for _, p := range parcels {
+		if !p.TrackStock {
+			continue
+		}
+		updated, err := dbops.AddParcelStock(DB, p.ParcelID, -used[p.ParcelID])
+		if err != nil {
+			log.Printf("useStock failed - %s: %v", p.ParcelID, err)
+			return alerts, err
+		}
+		if updated.OnHand < 0 {
+			log.Printf("useStock - %s: on hand count negative: %d", p.ParcelID, updated.OnHand)
+		}
+		if alert, ok := packops.Reorder(updated); ok {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts, nil
+}
+
+// newRateProvider returns the RateProvider of the shipping API set in SHIPPING_API; shippo is used if not set
+func newRateProvider() (rateops.RateProvider, error) {
+	switch api := strings.ToLower(os.Getenv(envarShippingAPI)); api {
+	case "", apiShippo:
+		token, err := shipops.GetToken("./stk.txt")
+		if err != nil {
+			log.Printf("newRateProvider failed: %v", err)
+			return nil, err
+		}
+		return rateops.NewShippo(shippo.NewClient(token)), nil
+	case apiEasyPost:
+		key, err := shipops.GetToken("./ept.txt")
+		if err != nil {
+			log.Printf("newRateProvider failed: %v", err)
+			return nil, err
+		}
+		return rateops.NewEasyPost(key), nil
+	default:
+		log.Printf("newRateProvider failed - unknown shipping API: %s", api)
+		return nil, fmt.Errorf("INVALID_SHIPPING_API")
+	}
+}
+
+func main() {
+	httpops.RegisterRoutes(route, RootHandler)
+	log.Fatal(gateway.ListenAndServe(":3000", nil))
+}
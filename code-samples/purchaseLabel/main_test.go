@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/packops"
+	"github.com/ggarcia209/acamoprjct/service/util/rateops"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+// testProvider implements the rateops.RateProvider interface offline, recording the parcels rated & the rate purchased
+type testProvider struct {
+	rates     []rateops.Rate
+	parcels   []packops.PackedParcel
+	purchased rateops.Rate
+}
+
+func (p *testProvider) CreateAddress(addr store.Address, validate bool) (rateops.Address, error) {
+	return rateops.Address{ID: addr.Zip, Address: addr, Valid: true}, nil
+}
+
+func (p *testProvider) CreateParcel(pp packops.PackedParcel) (rateops.Parcel, error) {
+	p.parcels = append(p.parcels, pp)
+	return rateops.Parcel{ID: fmt.Sprintf("prcl_%d", len(p.parcels))}, nil
+}
+
+func (p *testProvider) CreateShipment(from, to rateops.Address, parcels []rateops.Parcel) (rateops.Shipment, error) {
+	return rateops.Shipment{ID: "shp_1", Status: "SUCCESS", Rates: p.rates}, nil
+}
+
+func (p *testProvider) PurchaseLabel(rate rateops.Rate) ([]rateops.Label, error) {
+	p.purchased = rate
+	return []rateops.Label{rateops.Label{ID: "lbl_1", Status: "SUCCESS", TrackingNumber: "1Z001"}}, nil
+}
+
+func TestPurchaseLabels(t *testing.T) {
+	shipment := &store.Shipment{
+		OrderID: "order",
+		Rates: []store.RateSummary{
+			store.RateSummary{Carrier: "usps", PriceFloat: 8.0, OriginalPriceFloat: 8.0, Estimated: true, ServiceLevel: store.ServiceLevel{Token: "usps_priority"}},
+			store.RateSummary{Carrier: "ups", PriceFloat: 0.0, OriginalPriceFloat: 11.2, ServiceLevel: store.ServiceLevel{Token: "ups_ground"}},
+			store.RateSummary{Carrier: "pickup", Added: true, Promotion: "Local pickup", ServiceLevel: store.ServiceLevel{Token: "pickup_local"}},
+		},
+		Plans: map[string]store.PackingPlan{
+			"usps": store.PackingPlan{Packages: []store.Package{store.Package{Carrier: "usps", ParcelID: "usps_box", ActualWeightLb: 2.0}}},
+			"ups": store.PackingPlan{Packages: []store.Package{
+				store.Package{Carrier: "ups", ParcelID: "ups_box", ActualWeightLb: 3.0},
+				store.Package{Carrier: "ups", ParcelID: "ups_box", ActualWeightLb: 1.0},
+			}},
+		},
+	}
+	live := []rateops.Rate{
+		rateops.Rate{ID: "rate_1", Price: "8.40", PriceFloat: 8.4, ServiceLevel: store.ServiceLevel{Token: "usps_priority"}},
+		rateops.Rate{ID: "rate_2", Price: "11.20", PriceFloat: 11.2, ServiceLevel: store.ServiceLevel{Token: "ups_ground"}},
+	}
+
+	var tests = []struct {
+		token       string
+		rates       []rateops.Rate
+		wantErr     string
+		wantParcels []string
+		wantRate    string
+	}{
+		{token: "ups_ground", rates: live, wantParcels: []string{"ups_box", "ups_box"}, wantRate: "rate_2"},
+		{token: "usps_priority", rates: live, wantParcels: []string{"usps_box"}, wantRate: "rate_1"}, // estimated rate re-rated
+		{token: "fedex_ground", rates: live, wantErr: "NO_RATE_FOUND"},
+		{token: "pickup_local", rates: live, wantErr: "NO_LABEL_REQUIRED"},
+		{token: "ups_ground", rates: live[:1], wantErr: "RATE_UNAVAILABLE", wantParcels: []string{"ups_box", "ups_box"}},
+	}
+	for _, test := range tests {
+		rp := &testProvider{rates: test.rates}
+		labels, plan, err := purchaseLabels(rp, shipment, test.token)
+		if test.wantErr != "" {
+			if err == nil || err.Error() != test.wantErr {
+				t.Errorf("FAIL - %s: %v; want: %s", test.token, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("FAIL - %s: %v", test.token, err)
+			continue
+		}
+		if len(rp.parcels) != len(test.wantParcels) || len(plan.Packages) != len(test.wantParcels) {
+			t.Errorf("FAIL - %s parcels: %v; want: %v", test.token, rp.parcels, test.wantParcels)
+			continue
+		}
+		for i, p := range rp.parcels {
+			if p.Parcel.ParcelID != test.wantParcels[i] || p.Weight != units.Mass(plan.Packages[i].ActualWeightLb)*units.Pound {
+				t.Errorf("FAIL - %s parcel %d: %+v; want: %s", test.token, i, p, test.wantParcels[i])
+			}
+		}
+		if rp.purchased.ID != test.wantRate || len(labels) != 1 || labels[0].TrackingNumber != "1Z001" {
+			t.Errorf("FAIL - %s: purchased %s; labels: %v; want: %s", test.token, rp.purchased.ID, labels, test.wantRate)
+		}
+	}
+
+	// no plan for carrier
+	delete(shipment.Plans, "ups")
+	if _, _, err := purchaseLabels(&testProvider{rates: live}, shipment, "ups_ground"); err == nil || err.Error() != "NO_PLAN_FOUND" {
+		t.Errorf("FAIL - no plan: %v; want: NO_PLAN_FOUND", err)
+	}
+}
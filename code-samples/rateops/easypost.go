@@ -0,0 +1,361 @@
+package rateops
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/packops"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+// EasyPostURL is the base URL of the EasyPost API.
+const EasyPostURL = "https://api.easypost.com/v2"
+
+// easypostCarriers maps the EasyPost carrier names to the carrier tokens & provider names used by shippo,
+// so EasyPost rates are stored with the same meaning as shippo rates.
+var easypostCarriers = map[string]struct{ token, provider string }{
+	"USPS":       {store.CarriersUsps, "USPS"},
+	"UPS":        {"ups", "UPS"},
+	"FedEx":      {"fedex", "FedEx"},
+	"DHLExpress": {"dhl_express", "DHL Express"},
+}
+
+// EasyPost implements the RateProvider interface with the EasyPost API.
+// Shipments are created as EasyPost orders, so a shipment may contain multiple parcels.
+type EasyPost struct {
+	key    string // API key
+	url    string
+	client *http.Client
+}
+
+// NewEasyPost returns a RateProvider using the EasyPost API with the API key.
+func NewEasyPost(key string) *EasyPost {
+	return &EasyPost{key: key, url: EasyPostURL, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// easypostAddress represents an EasyPost address object.
+type easypostAddress struct {
+	ID            string `json:"id,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Company       string `json:"company,omitempty"`
+	Street1       string `json:"street1,omitempty"`
+	Street2       string `json:"street2,omitempty"`
+	City          string `json:"city,omitempty"`
+	State         string `json:"state,omitempty"`
+	Zip           string `json:"zip,omitempty"`
+	Country       string `json:"country,omitempty"`
+	Phone         string `json:"phone,omitempty"`
+	Email         string `json:"email,omitempty"`
+	Residential   bool   `json:"residential,omitempty"`
+	Verifications *struct {
+		Delivery *struct {
+			Success bool `json:"success"`
+			Errors  []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		} `json:"delivery"`
+	} `json:"verifications,omitempty"`
+}
+
+// easypostParcel represents an EasyPost parcel object; dimensions are in inches and weight is in ounces.
+type easypostParcel struct {
+	ID     string  `json:"id,omitempty"`
+	Length float32 `json:"length,omitempty"`
+	Width  float32 `json:"width,omitempty"`
+	Height float32 `json:"height,omitempty"`
+	Weight float32 `json:"weight,omitempty"`
+}
+
+// easypostRate represents a rate quoted for an EasyPost order or shipment.
+type easypostRate struct {
+	ID           string `json:"id"`
+	Carrier      string `json:"carrier"`
+	Service      string `json:"service"`
+	Rate         string `json:"rate"`
+	Currency     string `json:"currency"`
+	DeliveryDays int    `json:"delivery_days"`
+}
+
+// easypostShipment represents an EasyPost shipment within an order.
+type easypostShipment struct {
+	ID           string          `json:"id,omitempty"`
+	Parcel       *easypostParcel `json:"parcel,omitempty"`
+	TrackingCode string          `json:"tracking_code,omitempty"`
+	PostageLabel *struct {
+		LabelURL string `json:"label_url"`
+	} `json:"postage_label,omitempty"`
+}
+
+// easypostOrder represents an EasyPost order: a set of shipments between 2 addresses that are rated and bought together.
+type easypostOrder struct {
+	ID          string             `json:"id,omitempty"`
+	ToAddress   *easypostAddress   `json:"to_address,omitempty"`
+	FromAddress *easypostAddress   `json:"from_address,omitempty"`
+	Shipments   []easypostShipment `json:"shipments,omitempty"`
+	Rates       []easypostRate     `json:"rates,omitempty"`
+}
+
+// easypostError represents the error object returned by the EasyPost API.
+type easypostError struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// CreateAddress creates an EasyPost address object, verifying the address for delivery if validate is true.
+// Addresses are created as residential addresses.
+func (e *EasyPost) CreateAddress(addr store.Address, validate bool) (Address, error) {
+	body := map[string]interface{}{
+		"address": easypostAddress{
+			Name:        strings.TrimSpace(addr.FirstName + " " + addr.LastName),
+			Company:     addr.Company,
+			Street1:     addr.AddressLine1,
+			Street2:     addr.AddressLine2,
+			City:        addr.City,
+			State:       addr.State,
+			Zip:         addr.Zip,
+			Country:     addr.Country,
+			Phone:       addr.PhoneNumber,
+			Email:       addr.Email,
+			Residential: true,
+		},
+	}
+	if validate {
+		body["verify"] = []string{"delivery"}
+	}
+
+	a := easypostAddress{}
+	err := e.post("/addresses", body, &a)
+	if err != nil {
+		log.Printf("CreateAddress failed: %v", err)
+		return Address{}, err
+	}
+
+	address := Address{
+		ID: a.ID,
+		Address: store.Address{
+			FirstName:    a.Name,
+			Company:      a.Company,
+			AddressLine1: a.Street1,
+			AddressLine2: a.Street2,
+			City:         a.City,
+			State:        a.State,
+			Country:      a.Country,
+			Zip:          a.Zip,
+			PhoneNumber:  a.Phone,
+			Email:        a.Email,
+		},
+		Valid: !validate,
+	}
+	if validate && a.Verifications != nil && a.Verifications.Delivery != nil {
+		address.Valid = a.Verifications.Delivery.Success
+		for _, m := range a.Verifications.Delivery.Errors {
+			address.Messages = append(address.Messages, m.Message)
+		}
+	}
+	return address, nil
+}
+
+// CreateParcel creates an EasyPost parcel object for the packed parcel.
+func (e *EasyPost) CreateParcel(p packops.PackedParcel) (Parcel, error) {
+	in, err := easypostParcelInput(p)
+	if err != nil {
+		log.Printf("CreateParcel failed: %v", err)
+		return Parcel{}, err
+	}
+
+	parcel := easypostParcel{}
+	err = e.post("/parcels", map[string]interface{}{"parcel": in}, &parcel)
+	if err != nil {
+		log.Printf("CreateParcel failed: %v", err)
+		return Parcel{}, err
+	}
+	return Parcel{ID: parcel.ID}, nil
+}
+
+// create EasyPost parcel input from packed parcel
+func easypostParcelInput(p packops.PackedParcel) (easypostParcel, error) {
+	if p.Parcel == nil {
+		return easypostParcel{}, fmt.Errorf("NO_PARCEL_FOUND")
+	}
+	d := packops.BoundingBox(p.Parcel.ParcelDimensions) // tubes are sent as length x diameter x diameter
+	dims := [3]float32{}
+	for i, s := range []string{d.Length, d.Width, d.Height} {
+		l, err := units.ParseLength(s, d.DistanceUnit)
+		if err != nil {
+			return easypostParcel{}, err
+		}
+		dims[i] = l.Inches()
+	}
+	return easypostParcel{Length: dims[0], Width: dims[1], Height: dims[2], Weight: p.Weight.In(units.Ounce)}, nil
+}
+
+// CreateShipment creates an EasyPost order with 1 shipment per parcel, and returns the rates quoted for the order.
+func (e *EasyPost) CreateShipment(from, to Address, parcels []Parcel) (Shipment, error) {
+	order := easypostOrder{
+		ToAddress:   &easypostAddress{ID: to.ID},
+		FromAddress: &easypostAddress{ID: from.ID},
+	}
+	for _, p := range parcels {
+		order.Shipments = append(order.Shipments, easypostShipment{Parcel: &easypostParcel{ID: p.ID}})
+	}
+
+	created := easypostOrder{}
+	err := e.post("/orders", map[string]interface{}{"order": order}, &created)
+	if err != nil {
+		log.Printf("CreateShipment failed: %v", err)
+		return Shipment{}, err
+	}
+
+	rates := []Rate{}
+	for _, rate := range created.Rates {
+		carrier, ok := easypostCarriers[rate.Carrier]
+		if !ok {
+			carrier.token, carrier.provider = strings.ToLower(rate.Carrier), rate.Carrier
+		}
+		p, _ := strconv.ParseFloat(rate.Rate, 32)
+		rates = append(rates, Rate{
+			ID:         rate.ID,
+			ShipmentID: created.ID,
+			Provider:   carrier.provider,
+			ServiceLevel: store.ServiceLevel{
+				Name:  rate.Service,
+				Token: serviceToken(carrier.token, rate.Service),
+			},
+			Price:      rate.Rate,
+			PriceFloat: float32(p),
+			Currency:   rate.Currency,
+			Days:       rate.DeliveryDays,
+		})
+	}
+
+	// EasyPost orders have no status; shippo reports a rated shipment as "SUCCESS"
+	return Shipment{ID: created.ID, Status: "SUCCESS", Rates: rates}, nil
+}
+
+// easypostServices maps the EasyPost service names of each carrier to the shippo service level tokens
+// used by the rest of the store (rate tables, promotions, & customer selections).
+var easypostServices = map[string]map[string]string{
+	store.CarriersUsps: {
+		"First":                                 "usps_first",
+		"Priority":                              "usps_priority",
+		"Express":                               "usps_priority_express",
+		"GroundAdvantage":                       "usps_ground_advantage",
+		"ParcelSelect":                          "usps_parcel_select",
+		"MediaMail":                             "usps_media_mail",
+		"FirstClassPackageInternationalService": "usps_first_class_package_international_service",
+		"PriorityMailInternational":             "usps_priority_mail_international",
+		"ExpressMailInternational":              "usps_priority_mail_express_international",
+	},
+	"ups": {
+		"Ground":            "ups_ground",
+		"UPSStandard":       "ups_standard",
+		"UPSSaver":          "ups_saver",
+		"Express":           "ups_express",
+		"ExpressPlus":       "ups_express_plus",
+		"Expedited":         "ups_expedited",
+		"NextDayAir":        "ups_next_day_air",
+		"NextDayAirSaver":   "ups_next_day_air_saver",
+		"NextDayAirEarlyAM": "ups_next_day_air_early_am",
+		"2ndDayAir":         "ups_second_day_air",
+		"2ndDayAirAM":       "ups_second_day_air_am",
+		"3DaySelect":        "ups_3_day_select",
+	},
+	"fedex": {
+		"FEDEX_GROUND":           "fedex_ground",
+		"GROUND_HOME_DELIVERY":   "fedex_home_delivery",
+		"SMART_POST":             "fedex_smart_post",
+		"FEDEX_2_DAY":            "fedex_2_day",
+		"FEDEX_2_DAY_AM":         "fedex_2_day_am",
+		"FEDEX_EXPRESS_SAVER":    "fedex_express_saver",
+		"STANDARD_OVERNIGHT":     "fedex_standard_overnight",
+		"PRIORITY_OVERNIGHT":     "fedex_priority_overnight",
+		"FIRST_OVERNIGHT":        "fedex_first_overnight",
+		"INTERNATIONAL_ECONOMY":  "fedex_international_economy",
+		"INTERNATIONAL_PRIORITY": "fedex_international_priority",
+	},
+	"dhl_express": {
+		"ExpressWorldwide":       "dhl_express_worldwide",
+		"ExpressWorldwideNonDoc": "dhl_express_worldwide_nondoc",
+		"DomesticExpress":        "dhl_express_domestic_express_doc",
+		"EconomySelect":          "dhl_express_economy_select_doc",
+		"EconomySelectNonDoc":    "dhl_express_economy_select_nondoc",
+	},
+}
+
+// serviceToken returns the shippo service level token of the EasyPost service,
+// e.g. "usps_priority_express" for USPS "Express", or "fedex_ground" for FedEx "FEDEX_GROUND".
+// Services missing from easypostServices are logged, and returned as the lower case service name
+// prefixed with the carrier token; these tokens match no shippo rate.
+func serviceToken(carrier, service string) string {
+	if token, ok := easypostServices[carrier][service]; ok {
+		return token
+	}
+	log.Printf("serviceToken - %s %s: unknown EasyPost service", carrier, service)
+	return carrier + "_" + strings.ToLower(service)
+}
+
+// PurchaseLabel buys the rate's EasyPost order with the rate's carrier & service, and returns the label of each shipment.
+func (e *EasyPost) PurchaseLabel(rate Rate) ([]Label, error) {
+	carrier := rate.Provider
+	for name, c := range easypostCarriers {
+		if c.provider == rate.Provider {
+			carrier = name
+		}
+	}
+
+	order := easypostOrder{}
+	body := map[string]string{"carrier": carrier, "service": rate.ServiceLevel.Name}
+	err := e.post("/orders/"+rate.ShipmentID+"/buy", body, &order)
+	if err != nil {
+		log.Printf("PurchaseLabel failed: %v", err)
+		return []Label{}, err
+	}
+
+	labels := []Label{}
+	for _, s := range order.Shipments {
+		if s.PostageLabel == nil {
+			log.Printf("PurchaseLabel - %s: no postage label", s.ID)
+			return []Label{}, fmt.Errorf("LABEL_PURCHASE_FAILED")
+		}
+		labels = append(labels, Label{ID: s.ID, Status: "SUCCESS", TrackingNumber: s.TrackingCode, LabelURL: s.PostageLabel.LabelURL})
+	}
+	return labels, nil
+}
+
+// post sends the JSON body to the EasyPost API path and decodes the response into out.
+// An error is returned if the request fails or the API returns an error object.
+func (e *EasyPost) post(path string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, e.url+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(e.key, "")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		apiErr := easypostError{}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		log.Printf("easypost %s - %d %s: %s", path, resp.StatusCode, apiErr.Error.Code, apiErr.Error.Message)
+		return fmt.Errorf("EASYPOST_REQUEST_FAILED")
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
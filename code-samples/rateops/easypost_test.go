@@ -0,0 +1,168 @@
+package rateops
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/packops"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+// easypostStandIn returns a local HTTP stand-in for the EasyPost API, which records the request body of each path.
+func easypostStandIn(t *testing.T, bodies map[string]map[string]interface{}) *httptest.Server {
+	responses := map[string]string{
+		"/addresses": `{"id": "adr_1", "name": "Test Customer", "street1": "1 MAIN ST", "city": "LOS ANGELES", "state": "CA", "zip": "90001", "country": "US",
+			"verifications": {"delivery": {"success": false, "errors": [{"message": "Address not found"}]}}}`,
+		"/parcels": `{"id": "prcl_1"}`,
+		"/orders": `{"id": "order_1", "rates": [
+			{"id": "rate_1", "carrier": "USPS", "service": "Priority", "rate": "7.58", "currency": "USD", "delivery_days": 2},
+			{"id": "rate_2", "carrier": "FedEx", "service": "FEDEX_GROUND", "rate": "11.20", "currency": "USD", "delivery_days": 4},
+			{"id": "rate_3", "carrier": "DHLExpress", "service": "ExpressWorldwideNonDoc", "rate": "45.00", "currency": "USD", "delivery_days": 1}]}`,
+		"/orders/order_1/buy": `{"id": "order_1", "shipments": [
+			{"id": "shp_1", "tracking_code": "9400100000000000000001", "postage_label": {"label_url": "https://example.com/1.png"}},
+			{"id": "shp_2", "tracking_code": "9400100000000000000002", "postage_label": {"label_url": "https://example.com/2.png"}}]}`,
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if key, _, ok := r.BasicAuth(); !ok || key != "test_key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": {"code": "APIKEY.INACTIVE", "message": "invalid key"}}`))
+			return
+		}
+		resp, ok := responses[r.URL.Path]
+		if !ok || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"code": "NOT_FOUND", "message": "not found"}}`))
+			return
+		}
+		body := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("FAIL - %s body: %v", r.URL.Path, err)
+		}
+		bodies[r.URL.Path] = body
+		w.Write([]byte(resp))
+	}))
+}
+
+func TestEasyPost(t *testing.T) {
+	bodies := make(map[string]map[string]interface{})
+	srv := easypostStandIn(t, bodies)
+	defer srv.Close()
+	e := NewEasyPost("test_key")
+	e.url = srv.URL
+
+	// address verification
+	to, err := e.CreateAddress(store.Address{FirstName: "Test", LastName: "Customer", AddressLine1: "1 Main St", City: "Los Angeles", State: "CA", Zip: "90001", Country: "US"}, true)
+	if err != nil {
+		t.Errorf("FAIL - address: %v", err)
+		return
+	}
+	if to.ID != "adr_1" || to.Valid || len(to.Messages) != 1 || to.Address.AddressLine1 != "1 MAIN ST" {
+		t.Errorf("FAIL - address: %+v", to)
+	}
+	if verify, ok := bodies["/addresses"]["verify"].([]interface{}); !ok || len(verify) != 1 || verify[0] != "delivery" {
+		t.Errorf("FAIL - verify: %v", bodies["/addresses"]["verify"])
+	}
+
+	// parcel dimensions in inches & weight in ounces
+	p := packops.PackedParcel{
+		Parcel: &store.Parcel{ParcelDimensions: store.Dimensions{Length: "30.48", Width: "20.32", Height: "10.16", DistanceUnit: "cm", Weight: "1.0", MassUnit: "lb"}},
+		Weight: 2.0 * units.Pound,
+	}
+	parcel, err := e.CreateParcel(p)
+	if err != nil || parcel.ID != "prcl_1" {
+		t.Errorf("FAIL - parcel: %v; %v", parcel, err)
+		return
+	}
+	in := bodies["/parcels"]["parcel"].(map[string]interface{})
+	for k, want := range map[string]float64{"length": 12.0, "width": 8.0, "height": 4.0, "weight": 32.0} {
+		if got, _ := in[k].(float64); got < want-0.01 || got > want+0.01 {
+			t.Errorf("FAIL - parcel %s: %v; want: %v", k, in[k], want)
+		}
+	}
+
+	// rates map to shippo tokens & provider names
+	shipment, err := e.CreateShipment(Address{ID: "adr_0"}, to, []Parcel{parcel, parcel})
+	if err != nil {
+		t.Errorf("FAIL - shipment: %v", err)
+		return
+	}
+	if order := bodies["/orders"]["order"].(map[string]interface{}); len(order["shipments"].([]interface{})) != 2 {
+		t.Errorf("FAIL - order shipments: %v", order["shipments"])
+	}
+	want := []store.RateSummary{
+		store.RateSummary{Price: "7.58", PriceFloat: 7.58, Currency: "USD", Provider: "USPS", Carrier: "usps", Days: 2, ServiceLevel: store.ServiceLevel{Name: "Priority", Token: "usps_priority"}},
+		store.RateSummary{Price: "11.20", PriceFloat: 11.2, Currency: "USD", Provider: "FedEx", Carrier: "fedex", Days: 4, ServiceLevel: store.ServiceLevel{Name: "FEDEX_GROUND", Token: "fedex_ground"}},
+		store.RateSummary{Price: "45.00", PriceFloat: 45.0, Currency: "USD", Provider: "DHL Express", Carrier: "dhl_express", Days: 1, ServiceLevel: store.ServiceLevel{Name: "ExpressWorldwideNonDoc", Token: "dhl_express_worldwide_nondoc"}},
+	}
+	if shipment.ID != "order_1" || len(shipment.Rates) != len(want) {
+		t.Errorf("FAIL - shipment: %+v", shipment)
+		return
+	}
+	carriers := []string{"usps", "fedex", "dhl_express"}
+	for i, rate := range shipment.Rates {
+		if got := rate.Summary(carriers[i]); got != want[i] {
+			t.Errorf("FAIL - rate %d: %+v; want: %+v", i, got, want[i])
+		}
+	}
+
+	// labels for each parcel
+	labels, err := e.PurchaseLabel(shipment.Rates[2])
+	if err != nil || len(labels) != 2 || labels[1].TrackingNumber != "9400100000000000000002" {
+		t.Errorf("FAIL - labels: %+v; %v", labels, err)
+	}
+	if buy := bodies["/orders/order_1/buy"]; buy["carrier"] != "DHLExpress" || buy["service"] != "ExpressWorldwideNonDoc" {
+		t.Errorf("FAIL - buy: %v", buy)
+	}
+
+	// API errors
+	e.key = "bad_key"
+	if _, err := e.CreateParcel(p); err == nil || err.Error() != "EASYPOST_REQUEST_FAILED" {
+		t.Errorf("FAIL - bad key: %v; want: EASYPOST_REQUEST_FAILED", err)
+	}
+}
+
+func TestServiceToken(t *testing.T) {
+	// want: shippo service level tokens
+	var tests = []struct {
+		carrier string
+		service string
+		want    string
+	}{
+		{carrier: "usps", service: "Priority", want: "usps_priority"},
+		{carrier: "usps", service: "Express", want: "usps_priority_express"},
+		{carrier: "usps", service: "GroundAdvantage", want: "usps_ground_advantage"},
+		{carrier: "usps", service: "MediaMail", want: "usps_media_mail"},
+		{carrier: "ups", service: "Ground", want: "ups_ground"},
+		{carrier: "ups", service: "NextDayAir", want: "ups_next_day_air"},
+		{carrier: "ups", service: "2ndDayAirAM", want: "ups_second_day_air_am"},
+		{carrier: "ups", service: "3DaySelect", want: "ups_3_day_select"},
+		{carrier: "fedex", service: "FEDEX_GROUND", want: "fedex_ground"},
+		{carrier: "fedex", service: "GROUND_HOME_DELIVERY", want: "fedex_home_delivery"},
+		{carrier: "fedex", service: "FEDEX_2_DAY", want: "fedex_2_day"},
+		{carrier: "dhl_express", service: "ExpressWorldwide", want: "dhl_express_worldwide"},
+		{carrier: "usps", service: "LibraryMail", want: "usps_librarymail"}, // unknown service
+	}
+	for _, test := range tests {
+		if got := serviceToken(test.carrier, test.service); got != test.want {
+			t.Errorf("FAIL - %s %s: %s; want: %s", test.carrier, test.service, got, test.want)
+		}
+	}
+
+	// each service maps to a distinct token of its carrier
+	seen := make(map[string]string)
+	for carrier, services := range easypostServices {
+		for service, token := range services {
+			if !strings.HasPrefix(token, carrier+"_") {
+				t.Errorf("FAIL - %s %s: %s; want prefix: %s_", carrier, service, token, carrier)
+			}
+			if prev, ok := seen[token]; ok {
+				t.Errorf("FAIL - %s %s: %s; also mapped from: %s", carrier, service, token, prev)
+			}
+			seen[token] = service
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package rateops
+
+/* rateops defines carrier-neutral interfaces for the shipping API calls made to quote and ship an order:
+   address creation, parcel creation, rating, and label purchase. Each shipping API (e.g. shippo) is
+   supported by an adapter implementing the RateProvider interface, so callers depend only on the
+   interfaces and the types defined here, and not on any API client or its models.
+
+   Addresses, parcels, shipments, & labels are referenced by the provider's object IDs; a Parcel or
+   Address created with one RateProvider may not be used with another.
+*/
+
+import (
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/packops"
+)
+
+// Address represents an address created with a RateProvider.
+// Address contains the address as returned by the provider, which may be corrected by validation.
+// Valid is true if the address was validated by the provider, or validation was not requested.
+type Address struct {
+	ID       string
+	Address  store.Address
+	Valid    bool
+	Messages []string // validation messages
+}
+
+// Parcel represents a packed parcel created with a RateProvider.
+type Parcel struct {
+	ID string
+}
+
+// Rate represents a rate quoted for a Shipment by one of the carrier's service levels.
+// ServiceLevel.Token is the carrier token followed by the service, e.g. "usps_priority".
+type Rate struct {
+	ID           string
+	ShipmentID   string
+	Provider     string // carrier name, e.g. "USPS"
+	ServiceLevel store.ServiceLevel
+	Price        string
+	PriceFloat   float32
+	Currency     string
+	Days         int  // estimated transit days
+	Estimated    bool // true if priced offline; the rate must be re-rated before a label is purchased
+}
+
+// Shipment represents the shipment of a set of Parcels between 2 Addresses, and the Rates quoted for it.
+type Shipment struct {
+	ID     string
+	Status string
+	Rates  []Rate
+}
+
+// Label represents a shipping label document purchased for a Rate.
+type Label struct {
+	ID             string
+	Status         string
+	TrackingNumber string
+	LabelURL       string
+}
+
+// AddressCreator creates address objects, validating the address if requested.
+type AddressCreator interface {
+	CreateAddress(addr store.Address, validate bool) (Address, error)
+}
+
+// ParcelCreator creates a parcel object for each packed parcel.
+type ParcelCreator interface {
+	CreateParcel(p packops.PackedParcel) (Parcel, error)
+}
+
+// Rater creates a shipment of parcels between 2 addresses, and quotes its rates.
+type Rater interface {
+	CreateShipment(from, to Address, parcels []Parcel) (Shipment, error)
+}
+
+// LabelPurchaser purchases the shipping labels of a quoted rate; 1 Label is returned for each label document.
+type LabelPurchaser interface {
+	PurchaseLabel(rate Rate) ([]Label, error)
+}
+
+// RateProvider creates the addresses, parcels, and shipments needed to quote an order, and purchases labels.
+type RateProvider interface {
+	AddressCreator
+	ParcelCreator
+	Rater
+	LabelPurchaser
+}
+
+// Summary returns the store.RateSummary of the rate for storage on the store.Shipment.
+func (r Rate) Summary(carrier string) store.RateSummary {
+	return store.RateSummary{
+		Price:        r.Price,
+		PriceFloat:   r.PriceFloat,
+		Currency:     r.Currency,
+		Provider:     r.Provider,
+		Carrier:      carrier,
+		Days:         r.Days,
+		ServiceLevel: r.ServiceLevel,
+		Estimated:    r.Estimated,
+	}
+}
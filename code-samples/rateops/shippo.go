@@ -0,0 +1,160 @@
+package rateops
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/coldbrewcloud/go-shippo/client"
+	"github.com/coldbrewcloud/go-shippo/models"
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/packops"
+)
+
+// Shippo implements the RateProvider interface with the shippo API.
+type Shippo struct {
+	c *client.Client
+}
+
+// NewShippo returns a RateProvider using the shippo client.
+func NewShippo(c *client.Client) *Shippo {
+	return &Shippo{c: c}
+}
+
+// CreateAddress creates a shippo address object.
+// Addresses are created as residential addresses.
+func (s *Shippo) CreateAddress(addr store.Address, validate bool) (Address, error) {
+	ai := &models.AddressInput{
+		Name:          addr.FirstName + " " + addr.LastName,
+		Company:       addr.Company,
+		Street1:       addr.AddressLine1,
+		Street2:       addr.AddressLine2,
+		City:          addr.City,
+		Zip:           addr.Zip,
+		State:         addr.State,
+		Country:       addr.Country,
+		Phone:         addr.PhoneNumber,
+		Email:         addr.Email,
+		IsResidential: true,
+		Validate:      validate,
+	}
+	a, err := s.c.CreateAddress(ai)
+	if err != nil {
+		log.Printf("CreateAddress failed: %v", err)
+		return Address{}, err
+	}
+
+	address := Address{
+		ID: a.ObjectID,
+		Address: store.Address{
+			FirstName:    a.Name,
+			Company:      a.Company,
+			AddressLine1: a.Street1,
+			AddressLine2: a.Street2,
+			City:         a.City,
+			State:        a.State,
+			Country:      a.Country,
+			Zip:          a.Zip,
+			PhoneNumber:  a.Phone,
+			Email:        a.Email,
+		},
+		Valid: !validate,
+	}
+	if validate && a.ValidationResults != nil {
+		address.Valid = a.ValidationResults.IsValid
+		for _, m := range a.ValidationResults.Messages {
+			address.Messages = append(address.Messages, m.Text)
+		}
+	}
+	return address, nil
+}
+
+// CreateParcel creates a shippo parcel object for the packed parcel.
+func (s *Shippo) CreateParcel(p packops.PackedParcel) (Parcel, error) {
+	parcel, err := s.c.CreateParcel(shippoParcelInput(p))
+	if err != nil {
+		log.Printf("CreateParcel failed: %v", err)
+		return Parcel{}, err
+	}
+	return Parcel{ID: parcel.ObjectID}, nil
+}
+
+// create shippo parcel input from packed parcel
+func shippoParcelInput(p packops.PackedParcel) *models.ParcelInput {
+	if p.Parcel == nil {
+		return &models.ParcelInput{}
+	}
+	d := packops.BoundingBox(p.Parcel.ParcelDimensions) // tubes are sent as length x diameter x diameter
+	return &models.ParcelInput{
+		Length:       d.Length,
+		Width:        d.Width,
+		Height:       d.Height,
+		DistanceUnit: d.DistanceUnit,
+		Weight:       fmt.Sprintf("%.2f", p.Weight.Pounds()),
+		MassUnit:     "lb",
+	}
+}
+
+// CreateShipment creates a shippo shipment object and returns the rates quoted by each connected carrier account.
+func (s *Shippo) CreateShipment(from, to Address, parcels []Parcel) (Shipment, error) {
+	parcelIDs := []string{}
+	for _, p := range parcels {
+		parcelIDs = append(parcelIDs, p.ID)
+	}
+
+	shipment, err := s.c.CreateShipment(&models.ShipmentInput{
+		AddressFrom: from.ID,
+		AddressTo:   to.ID,
+		Parcels:     parcelIDs,
+	})
+	if err != nil {
+		log.Printf("CreateShipment failed: %v", err)
+		return Shipment{}, err
+	}
+
+	rates := []Rate{}
+	for _, rate := range shipment.Rates {
+		sl := store.ServiceLevel{}
+		if rate.ServiceLevel != nil {
+			sl = store.ServiceLevel{
+				Name:  rate.ServiceLevel.Name,
+				Token: rate.ServiceLevel.Token,
+				Terms: rate.ServiceLevel.Terms,
+			}
+		}
+		p, _ := strconv.ParseFloat(rate.AmountLocal, 32)
+		rates = append(rates, Rate{
+			ID:           rate.ObjectID,
+			ShipmentID:   shipment.ObjectID,
+			Provider:     rate.Provider,
+			ServiceLevel: sl,
+			Price:        rate.AmountLocal,
+			PriceFloat:   float32(p),
+			Currency:     rate.Currency,
+			Days:         rate.Days,
+		})
+	}
+
+	return Shipment{ID: shipment.ObjectID, Status: shipment.Status, Rates: rates}, nil
+}
+
+// PurchaseLabel purchases a PDF shipping label for the rate; multi-parcel shipments are labeled in 1 document.
+// An error is returned if the label is not created.
+func (s *Shippo) PurchaseLabel(rate Rate) ([]Label, error) {
+	t, err := s.c.PurchaseShippingLabel(&models.TransactionInput{
+		Rate:          rate.ID,
+		LabelFileType: "PDF",
+		Async:         false,
+	})
+	if err != nil {
+		log.Printf("PurchaseLabel failed: %v", err)
+		return []Label{}, err
+	}
+	if t.Status != "SUCCESS" {
+		for _, m := range t.Messages {
+			log.Printf("PurchaseLabel - %s: %s", m.Source, m.Text)
+		}
+		return []Label{}, fmt.Errorf("LABEL_PURCHASE_FAILED")
+	}
+	return []Label{Label{ID: t.ObjectID, Status: t.Status, TrackingNumber: t.TrackingNumber, LabelURL: t.LabelURL}}, nil
+}
@@ -0,0 +1,43 @@
+package rateops
+
+import (
+	"testing"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/packops"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+func TestShippoParcelInput(t *testing.T) {
+	var tests = []struct {
+		parcel     packops.PackedParcel
+		wantDims   [3]string
+		wantWeight string
+	}{
+		{
+			parcel: packops.PackedParcel{
+				Parcel: &store.Parcel{ParcelDimensions: store.Dimensions{Length: "12.0", Width: "12.0", Height: "6.0", DistanceUnit: "in", Weight: "1.0", MassUnit: "lb"}},
+				Weight: 2.5 * units.Pound,
+			},
+			wantDims:   [3]string{"12.0", "12.0", "6.0"},
+			wantWeight: "2.50",
+		},
+		{ // tubes are sent as their bounding box
+			parcel: packops.PackedParcel{
+				Parcel: &store.Parcel{ParcelDimensions: store.Dimensions{Shape: packops.ShapeCylinder, Length: "26.0", Diameter: "4.0", DistanceUnit: "in", Weight: "0.3", MassUnit: "lb"}},
+				Weight: 16 * units.Ounce,
+			},
+			wantDims:   [3]string{"26.0", "4.0", "4.0"},
+			wantWeight: "1.00",
+		},
+	}
+	for i, test := range tests {
+		in := shippoParcelInput(test.parcel)
+		if got := [3]string{in.Length, in.Width, in.Height}; got != test.wantDims {
+			t.Errorf("FAIL - case %d: dimensions: %v; want: %v", i, got, test.wantDims)
+		}
+		if in.DistanceUnit != "in" || in.Weight != test.wantWeight || in.MassUnit != "lb" {
+			t.Errorf("FAIL - case %d: weight: %s %s; want: %s lb", i, in.Weight, in.MassUnit, test.wantWeight)
+		}
+	}
+}
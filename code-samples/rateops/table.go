@@ -0,0 +1,114 @@
+package rateops
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/packops"
+)
+
+// TableRates implements the RateProvider interface offline with the rate table of each service level,
+// for quoting orders when the shipping API is unavailable. Rates are priced from the weight & zone tiers
+// of each table and marked as estimated; labels can not be purchased with TableRates, and must be
+// re-rated with the shipping API at purchase time.
+//
+// Each rate table's ServiceLevel.Token must begin with the table's carrier token (e.g. "usps_priority").
+// Addresses are not validated. TableRates is not safe for concurrent use.
+type TableRates struct {
+	Tables  []*store.RateTable
+	parcels map[string]packops.PackedParcel // created parcels by ID
+}
+
+// NewTableRates returns a RateProvider pricing shipments with the rate tables.
+func NewTableRates(tables []*store.RateTable) *TableRates {
+	return &TableRates{Tables: tables, parcels: make(map[string]packops.PackedParcel)}
+}
+
+// CreateAddress returns the address unchanged; addresses can not be validated offline.
+func (t *TableRates) CreateAddress(addr store.Address, validate bool) (Address, error) {
+	return Address{ID: "table-address-" + addr.Zip, Address: addr, Valid: true}, nil
+}
+
+// CreateParcel stores the packed parcel for rating.
+func (t *TableRates) CreateParcel(p packops.PackedParcel) (Parcel, error) {
+	id := fmt.Sprintf("table-parcel-%d", len(t.parcels))
+	t.parcels[id] = p
+	return Parcel{ID: id}, nil
+}
+
+// CreateShipment returns the estimated rate of each rate table for the parcels shipped to the address.
+// Service levels with no tier for a parcel's billable weight are not quoted.
+func (t *TableRates) CreateShipment(from, to Address, parcels []Parcel) (Shipment, error) {
+	rates := []Rate{}
+	for _, table := range t.Tables {
+		zone := tableZone(table, to.Address.Zip)
+		total := float32(0.0)
+		ok := true
+		for _, parcel := range parcels {
+			p, found := t.parcels[parcel.ID]
+			if !found {
+				log.Printf("CreateShipment failed - unknown parcel: %s", parcel.ID)
+				return Shipment{}, fmt.Errorf("NO_PARCEL_FOUND")
+			}
+			price, err := tablePrice(table, zone, p.BillableWeight().Pounds())
+			if err != nil {
+				log.Printf("CreateShipment - %s zone %d: %v", table.ServiceLevel.Token, zone, err)
+				ok = false
+				break
+			}
+			total += price
+		}
+		if !ok {
+			continue
+		}
+		rates = append(rates, Rate{
+			Provider:     table.Provider,
+			ServiceLevel: table.ServiceLevel,
+			Price:        fmt.Sprintf("%.2f", total),
+			PriceFloat:   total,
+			Currency:     "USD",
+			Days:         table.Days,
+			Estimated:    true,
+		})
+	}
+	return Shipment{Status: "SUCCESS", Rates: rates}, nil
+}
+
+// PurchaseLabel returns an error; estimated rates must be re-rated with the shipping API.
+func (t *TableRates) PurchaseLabel(rate Rate) ([]Label, error) {
+	return []Label{}, fmt.Errorf("ESTIMATED_RATE")
+}
+
+// tableZone returns the zone of the table's longest zip code prefix matching the destination zip code.
+// 0 is returned if no prefix matches.
+func tableZone(table *store.RateTable, zip string) int {
+	zone, longest := 0, -1
+	for _, z := range table.Zones {
+		if strings.HasPrefix(zip, z.ZipPrefix) && len(z.ZipPrefix) > longest {
+			zone, longest = z.Zone, len(z.ZipPrefix)
+		}
+	}
+	return zone
+}
+
+// tablePrice returns the price of the least weight tier of the zone that covers the billable weight in lbs.
+// Tiers with Zone 0 apply to every zone, and are used if the zone has no tier for the weight.
+func tablePrice(table *store.RateTable, zone int, billableLb float32) (float32, error) {
+	var best *store.RateTier
+	for i := range table.Tiers {
+		tier := &table.Tiers[i]
+		if (tier.Zone != zone && tier.Zone != 0) || billableLb > tier.MaxWeightLb {
+			continue
+		}
+		if best == nil || (tier.Zone != 0 && best.Zone == 0) ||
+			(tier.Zone == best.Zone && tier.MaxWeightLb < best.MaxWeightLb) {
+			best = tier
+		}
+	}
+	if best == nil {
+		return 0, fmt.Errorf("NO_TABLE_RATE")
+	}
+	return best.Price, nil
+}
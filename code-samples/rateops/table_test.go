@@ -0,0 +1,107 @@
+package rateops
+
+import (
+	"testing"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/packops"
+	"github.com/ggarcia209/acamoprjct/service/util/units"
+)
+
+var testRateTables = []*store.RateTable{
+	&store.RateTable{
+		Carrier:      "usps",
+		Provider:     "USPS",
+		ServiceLevel: store.ServiceLevel{Name: "Priority Mail", Token: "usps_priority"},
+		Days:         2,
+		Zones: []store.RateZone{
+			store.RateZone{ZipPrefix: "9", Zone: 2},
+			store.RateZone{ZipPrefix: "1", Zone: 8},
+			store.RateZone{ZipPrefix: "100", Zone: 7},
+		},
+		Tiers: []store.RateTier{
+			store.RateTier{Zone: 2, MaxWeightLb: 1.0, Price: 8.00},
+			store.RateTier{Zone: 2, MaxWeightLb: 5.0, Price: 10.00},
+			store.RateTier{Zone: 7, MaxWeightLb: 5.0, Price: 15.00},
+			store.RateTier{Zone: 8, MaxWeightLb: 1.0, Price: 12.00},
+			store.RateTier{Zone: 0, MaxWeightLb: 20.0, Price: 30.00},
+		},
+	},
+	&store.RateTable{
+		Carrier:      "usps",
+		Provider:     "USPS",
+		ServiceLevel: store.ServiceLevel{Name: "Ground Advantage", Token: "usps_ground_advantage"},
+		Days:         5,
+		Tiers: []store.RateTier{
+			store.RateTier{Zone: 0, MaxWeightLb: 2.0, Price: 5.50},
+		},
+	},
+}
+
+func TestTableRates(t *testing.T) {
+	var tests = []struct {
+		zip     string
+		weights []float32 // billable lbs of each parcel
+		want    []store.RateSummary
+	}{
+		// least covering tier of the zone
+		{zip: "90001", weights: []float32{0.5}, want: []store.RateSummary{
+			store.RateSummary{Price: "8.00", PriceFloat: 8.0, Currency: "USD", Provider: "USPS", Carrier: "usps", Days: 2, Estimated: true, ServiceLevel: testRateTables[0].ServiceLevel},
+			store.RateSummary{Price: "5.50", PriceFloat: 5.5, Currency: "USD", Provider: "USPS", Carrier: "usps", Days: 5, Estimated: true, ServiceLevel: testRateTables[1].ServiceLevel},
+		}},
+		// parcels are priced separately; ground advantage has no tier for 3 lbs
+		{zip: "90001", weights: []float32{0.5, 3.0}, want: []store.RateSummary{
+			store.RateSummary{Price: "18.00", PriceFloat: 18.0, Currency: "USD", Provider: "USPS", Carrier: "usps", Days: 2, Estimated: true, ServiceLevel: testRateTables[0].ServiceLevel},
+		}},
+		// longest zip prefix
+		{zip: "10001", weights: []float32{3.0}, want: []store.RateSummary{
+			store.RateSummary{Price: "15.00", PriceFloat: 15.0, Currency: "USD", Provider: "USPS", Carrier: "usps", Days: 2, Estimated: true, ServiceLevel: testRateTables[0].ServiceLevel},
+		}},
+		// zone 0 tiers cover weights over the zone's tiers
+		{zip: "11201", weights: []float32{3.0}, want: []store.RateSummary{
+			store.RateSummary{Price: "30.00", PriceFloat: 30.0, Currency: "USD", Provider: "USPS", Carrier: "usps", Days: 2, Estimated: true, ServiceLevel: testRateTables[0].ServiceLevel},
+		}},
+		// no service level covers the weight
+		{zip: "90001", weights: []float32{25.0}, want: []store.RateSummary{}},
+	}
+	for _, test := range tests {
+		tr := NewTableRates(testRateTables)
+		to, err := tr.CreateAddress(store.Address{Zip: test.zip, Country: "US"}, true)
+		if err != nil || !to.Valid {
+			t.Errorf("FAIL - address: %+v; %v", to, err)
+			continue
+		}
+		parcels := []Parcel{}
+		for _, w := range test.weights {
+			p, err := tr.CreateParcel(packops.PackedParcel{Weight: units.Mass(w) * units.Pound})
+			if err != nil {
+				t.Errorf("FAIL - parcel: %v", err)
+			}
+			parcels = append(parcels, p)
+		}
+		shipment, err := tr.CreateShipment(Address{}, to, parcels)
+		if err != nil {
+			t.Errorf("FAIL - %s %v: %v", test.zip, test.weights, err)
+			continue
+		}
+		if len(shipment.Rates) != len(test.want) {
+			t.Errorf("FAIL - %s %v: %+v; want: %+v", test.zip, test.weights, shipment.Rates, test.want)
+			continue
+		}
+		for i, rate := range shipment.Rates {
+			if got := rate.Summary("usps"); got != test.want[i] {
+				t.Errorf("FAIL - %s %v: %+v; want: %+v", test.zip, test.weights, got, test.want[i])
+			}
+		}
+	}
+
+	// parcels of another provider
+	tr := NewTableRates(testRateTables)
+	if _, err := tr.CreateShipment(Address{}, Address{}, []Parcel{Parcel{ID: "prcl_1"}}); err == nil || err.Error() != "NO_PARCEL_FOUND" {
+		t.Errorf("FAIL - unknown parcel: %v; want: NO_PARCEL_FOUND", err)
+	}
+	// estimated rates can not be purchased
+	if _, err := tr.PurchaseLabel(Rate{Estimated: true}); err == nil || err.Error() != "ESTIMATED_RATE" {
+		t.Errorf("FAIL - label: %v; want: ESTIMATED_RATE", err)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+/* simulateParcels replays historical orders through the packing engine against alternative parcel catalogs,
+   to determine which box sizes are worth stocking. Each order is packed the same way getShippingMethods
+   packs an order for a shipping quote (packops.PackOrder), and the parcel counts, fill ratios, and
+   estimated postage are reported for each catalog.
+
+   Orders are read from a JSON export of the Orders table (an array of store.Order objects), and each
+   catalog is read from a JSON file containing an array of store.Parcel objects.
+   Postage is estimated offline with packops.DefaultTableRates. Orders the estimator cannot price are
+   reported as unpriced, and are left out of the postage totals.
+
+   Usage:
+     simulateParcels -orders orders.json -catalog current.json -catalog no-large.json
+*/
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/packops"
+)
+
+// catalogFlags contains the path of each catalog file passed with -catalog.
+type catalogFlags []string
+
+func (c *catalogFlags) String() string {
+	return strings.Join(*c, ",")
+}
+
+func (c *catalogFlags) Set(path string) error {
+	*c = append(*c, path)
+	return nil
+}
+
+// report represents the simulated packing of every order with a parcel catalog.
+type report struct {
+	Catalog  string
+	Orders   int
+	Failed   int            // orders that could not be packed
+	Unpriced int            // packed orders the estimator could not price
+	Parcels  map[string]int // parcels used per ParcelID
+	Fill     map[string]float32
+	Postage  float32 // estimated postage of priced orders
+}
+
+// loadOrders reads the orders from a JSON export of the Orders table.
+func loadOrders(path string) ([]*store.Order, error) {
+	orders := []*store.Order{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Printf("loadOrders failed: %v", err)
+		return orders, err
+	}
+	err = json.Unmarshal(data, &orders)
+	if err != nil {
+		log.Printf("loadOrders failed: %v", err)
+		return []*store.Order{}, err
+	}
+	return orders, nil
+}
+
+// loadCatalog reads the parcels of a catalog from a JSON file.
+func loadCatalog(path string) ([]*store.Parcel, error) {
+	parcels := []*store.Parcel{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Printf("loadCatalog failed: %v", err)
+		return parcels, err
+	}
+	err = json.Unmarshal(data, &parcels)
+	if err != nil {
+		log.Printf("loadCatalog failed: %v", err)
+		return []*store.Parcel{}, err
+	}
+	return parcels, nil
+}
+
+// simulate packs each order with the catalog's parcels and returns the report.
+// Fill contains the mean fill ratio of each ParcelID.
+func simulate(name string, orders []*store.Order, parcels []*store.Parcel, pl packops.Planner, est packops.RateEstimator) report {
+	r := report{Catalog: name, Parcels: make(map[string]int), Fill: make(map[string]float32)}
+	for _, order := range orders {
+		r.Orders++
+		plan, err := packops.PackOrder(pl, est, order.Items, parcels, store.CarriersUsps, time.Now().Add(packops.DefaultExactBudget))
+		if err != nil {
+			log.Printf("simulate - %s: order %s failed: %v", name, order.OrderID, err)
+			r.Failed++
+			continue
+		}
+		cost, err := est.EstimatePlan(plan.Parcels)
+		if err != nil {
+			log.Printf("simulate - %s: order %s estimate failed: %v", name, order.OrderID, err)
+			r.Unpriced++
+		} else {
+			r.Postage += cost
+		}
+
+		for _, p := range plan.Parcels {
+			fill, err := p.FillRatio()
+			if err != nil {
+				log.Printf("simulate - %s: order %s fill ratio failed: %v", name, order.OrderID, err)
+			}
+			r.Parcels[p.Package.ParcelID]++
+			r.Fill[p.Package.ParcelID] += fill
+		}
+	}
+	for id, n := range r.Parcels {
+		r.Fill[id] /= float32(n)
+	}
+	return r
+}
+
+// print writes the report as a table.
+func (r report) print(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	packed := r.Orders - r.Failed
+	priced := packed - r.Unpriced
+	total := 0
+	fill := float32(0.0)
+	ids := []string{}
+	for id, n := range r.Parcels {
+		ids = append(ids, id)
+		total += n
+		fill += r.Fill[id] * float32(n)
+	}
+	sort.Strings(ids)
+
+	fmt.Fprintf(tw, "catalog:\t%s\n", r.Catalog)
+	fmt.Fprintf(tw, "orders:\t%d\t(%d failed, %d unpriced)\n", r.Orders, r.Failed, r.Unpriced)
+	fmt.Fprintf(tw, "parcels:\t%d\t(%.2f per order)\n", total, ratio(float32(total), packed))
+	for _, id := range ids {
+		fmt.Fprintf(tw, "  %s\t%d\tfill %.1f%%\n", id, r.Parcels[id], r.Fill[id]*100)
+	}
+	fmt.Fprintf(tw, "fill ratio:\t%.1f%%\n", ratio(fill, total)*100)
+	fmt.Fprintf(tw, "estimated postage:\t$%.2f\t($%.2f per priced order)\n", r.Postage, ratio(r.Postage, priced))
+	fmt.Fprintln(tw)
+	tw.Flush()
+}
+
+// ratio returns f / n, or 0 if n is 0.
+func ratio(f float32, n int) float32 {
+	if n == 0 {
+		return 0
+	}
+	return f / float32(n)
+}
+
+func main() {
+	ordersPath := flag.String("orders", "", "path to JSON export of orders")
+	catalogs := catalogFlags{}
+	flag.Var(&catalogs, "catalog", "path to JSON parcel catalog; may be repeated")
+	flag.Parse()
+
+	if *ordersPath == "" || len(catalogs) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	orders, err := loadOrders(*ordersPath)
+	if err != nil {
+		log.Fatalf("simulateParcels failed: %v", err)
+	}
+
+	pl := packops.NewPlanner(packops.NewPacker())
+	for _, path := range catalogs {
+		parcels, err := loadCatalog(path)
+		if err != nil {
+			log.Fatalf("simulateParcels failed: %v", err)
+		}
+		r := simulate(filepath.Base(path), orders, parcels, pl, packops.DefaultTableRates)
+		r.print(os.Stdout)
+	}
+}
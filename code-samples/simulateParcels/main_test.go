@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ggarcia209/acamoprjct/service/store-api/store"
+	"github.com/ggarcia209/acamoprjct/service/util/packops"
+)
+
+func TestSimulate(t *testing.T) {
+	medium := &store.Parcel{
+		Carrier:          store.CarriersUsps,
+		ParcelID:         "medium",
+		ParcelDimensions: store.Dimensions{Length: "7", Width: "7", Height: "6", DistanceUnit: "in", Weight: "0.5", MassUnit: "lb", Volume: 294},
+	}
+	large := &store.Parcel{
+		Carrier:          store.CarriersUsps,
+		ParcelID:         "large",
+		ParcelDimensions: store.Dimensions{Length: "14", Width: "8", Height: "6", DistanceUnit: "in", Weight: "1", MassUnit: "lb", Volume: 672},
+	}
+	orders := []*store.Order{
+		&store.Order{
+			OrderID: "1",
+			Items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "006",
+					SizeID:             "006-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "6", Width: "6", Height: "5", DistanceUnit: "in", Weight: "2", MassUnit: "lb", Volume: 180},
+				},
+			},
+		},
+		&store.Order{
+			OrderID: "2",
+			Items: []*store.CartItem{
+				&store.CartItem{
+					ItemID:             "008",
+					SizeID:             "008-OS",
+					Quantity:           1,
+					ShippingDimensions: store.Dimensions{Length: "12", Width: "7", Height: "5", DistanceUnit: "in", Weight: "3", MassUnit: "lb", Volume: 420},
+				},
+			},
+		},
+	}
+
+	// light prices parcels up to 3 lb; order 2 packs 4 lb in the large box & is left unpriced
+	light := &packops.TableRateEstimator{Rates: map[string][]packops.TableRate{
+		store.CarriersUsps: []packops.TableRate{packops.TableRate{MaxWeightLb: 3.0, Price: 10.50}},
+	}}
+
+	var tests = []struct {
+		parcels      []*store.Parcel
+		est          packops.RateEstimator
+		wantFailed   int
+		wantUnpriced int
+		wantPostage  float32
+		wantParcels  map[string]int
+	}{
+		{parcels: []*store.Parcel{medium, large}, est: packops.DefaultTableRates, wantFailed: 0, wantPostage: 26.30, wantParcels: map[string]int{"medium": 1, "large": 1}},
+		{parcels: []*store.Parcel{medium}, est: packops.DefaultTableRates, wantFailed: 1, wantPostage: 11.80, wantParcels: map[string]int{"medium": 1}},
+		{parcels: []*store.Parcel{medium, large}, est: light, wantFailed: 0, wantUnpriced: 1, wantPostage: 10.50, wantParcels: map[string]int{"medium": 1, "large": 1}},
+	}
+	pl := packops.NewPlanner(packops.NewPacker())
+	for i, test := range tests {
+		r := simulate("test", orders, test.parcels, pl, test.est)
+		if r.Orders != 2 || r.Failed != test.wantFailed || r.Unpriced != test.wantUnpriced {
+			t.Errorf("FAIL - case %d: orders: %d; failed: %d; unpriced: %d; want: 2, %d, %d", i, r.Orders, r.Failed, r.Unpriced, test.wantFailed, test.wantUnpriced)
+		}
+		for id, n := range test.wantParcels {
+			if r.Parcels[id] != n {
+				t.Errorf("FAIL - case %d: %s: %d; want: %d", i, id, r.Parcels[id], n)
+			}
+			if r.Fill[id] <= 0 || r.Fill[id] > 1 {
+				t.Errorf("FAIL - case %d: %s fill: %f", i, id, r.Fill[id])
+			}
+		}
+		if d := r.Postage - test.wantPostage; d > 0.001 || d < -0.001 {
+			t.Errorf("FAIL - case %d: postage: %f; want: %f", i, r.Postage, test.wantPostage)
+		}
+
+		buf := &bytes.Buffer{}
+		r.print(buf)
+		if !strings.Contains(buf.String(), "estimated postage") {
+			t.Errorf("FAIL - case %d: report: %s", i, buf.String())
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package units
+
+/* units contains typed length, mass, & volume quantities and the conversions between their units.
+   Quantities are stored in a single base unit (mm, g, & cubic mm), so quantities measured in different
+   units may be compared and combined directly. Strings are only parsed and formatted at the boundaries
+   of the application (store.Dimensions, carrier APIs).
+*/
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Length represents a distance in mm.
+type Length float32
+
+// Length units.
+const (
+	Millimeter Length = 1.0
+	Centimeter Length = 10.0
+	Inch       Length = 25.4
+	Foot       Length = 304.8
+)
+
+// Mass represents a mass in grams.
+type Mass float32
+
+// Mass units.
+const (
+	Gram     Mass = 1.0
+	Kilogram Mass = 1000.0
+	Ounce    Mass = 28.349523125
+	Pound    Mass = 453.59237
+)
+
+// Volume represents a volume in cubic mm.
+type Volume float32
+
+// Volume units.
+const (
+	CubicMillimeter Volume = 1.0
+	CubicCentimeter Volume = 1000.0
+	CubicInch       Volume = 16387.064
+	CubicFoot       Volume = 28316846.592
+)
+
+// LengthUnit returns the Length of 1 unit of the distance unit symbol (mm, cm, in, ft).
+func LengthUnit(symbol string) (Length, error) {
+	switch strings.ToLower(symbol) {
+	case "mm":
+		return Millimeter, nil
+	case "cm":
+		return Centimeter, nil
+	case "in":
+		return Inch, nil
+	case "ft":
+		return Foot, nil
+	}
+	return 0, fmt.Errorf("INVALID_DISTANCE_UNIT")
+}
+
+// MassUnit returns the Mass of 1 unit of the mass unit symbol (g, kg, oz, lb).
+func MassUnit(symbol string) (Mass, error) {
+	switch strings.ToLower(symbol) {
+	case "g":
+		return Gram, nil
+	case "kg":
+		return Kilogram, nil
+	case "oz":
+		return Ounce, nil
+	case "lb":
+		return Pound, nil
+	}
+	return 0, fmt.Errorf("INVALID_MASS_UNIT")
+}
+
+// ParseLength returns the Length of the decimal string s measured in the distance unit symbol.
+func ParseLength(s, symbol string) (Length, error) {
+	unit, err := LengthUnit(symbol)
+	if err != nil {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 32)
+	if err != nil {
+		return 0, err
+	}
+	return Length(f) * unit, nil
+}
+
+// ParseMass returns the Mass of the decimal string s measured in the mass unit symbol.
+func ParseMass(s, symbol string) (Mass, error) {
+	unit, err := MassUnit(symbol)
+	if err != nil {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 32)
+	if err != nil {
+		return 0, err
+	}
+	return Mass(f) * unit, nil
+}
+
+// In returns the Length measured in unit.
+func (l Length) In(unit Length) float32 {
+	return float32(l / unit)
+}
+
+// Inches returns the Length in inches.
+func (l Length) Inches() float32 {
+	return l.In(Inch)
+}
+
+// Format returns the Length in the distance unit symbol as a decimal string with 2 decimal places.
+func (l Length) Format(symbol string) (string, error) {
+	unit, err := LengthUnit(symbol)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(float64(l.In(unit)), 'f', 2, 32), nil
+}
+
+// In returns the Mass measured in unit.
+func (m Mass) In(unit Mass) float32 {
+	return float32(m / unit)
+}
+
+// Pounds returns the Mass in lbs.
+func (m Mass) Pounds() float32 {
+	return m.In(Pound)
+}
+
+// Format returns the Mass in the mass unit symbol as a decimal string with 2 decimal places.
+func (m Mass) Format(symbol string) (string, error) {
+	unit, err := MassUnit(symbol)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(float64(m.In(unit)), 'f', 2, 32), nil
+}
+
+// Cube returns the Volume of a box with the given dimensions.
+func Cube(l, w, h Length) Volume {
+	return Volume(l) * Volume(w) * Volume(h)
+}
+
+// In returns the Volume measured in unit.
+func (v Volume) In(unit Volume) float32 {
+	return float32(v / unit)
+}
+
+// CubicInches returns the Volume in cubic inches.
+func (v Volume) CubicInches() float32 {
+	return v.In(CubicInch)
+}
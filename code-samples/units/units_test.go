@@ -0,0 +1,72 @@
+package units
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestParseLength(t *testing.T) {
+	var tests = []struct {
+		s       string
+		unit    string
+		wantMM  float32
+		wantErr error
+	}{
+		{s: "1.0", unit: "in", wantMM: 25.4},
+		{s: "2.5", unit: "cm", wantMM: 25.0},
+		{s: "12", unit: "mm", wantMM: 12.0},
+		{s: "1", unit: "ft", wantMM: 304.8},
+		{s: " 3.0 ", unit: "IN", wantMM: 76.2},
+		{s: "1.0", unit: "yd", wantErr: fmt.Errorf("INVALID_DISTANCE_UNIT")},
+	}
+	for _, test := range tests {
+		l, err := ParseLength(test.s, test.unit)
+		if fmt.Sprint(err) != fmt.Sprint(test.wantErr) {
+			t.Errorf("FAIL - %s %s: %v; want: %v", test.s, test.unit, err, test.wantErr)
+			continue
+		}
+		if math.Abs(float64(l.In(Millimeter)-test.wantMM)) > 0.001 {
+			t.Errorf("FAIL - %s %s: %f mm; want: %f", test.s, test.unit, l.In(Millimeter), test.wantMM)
+		}
+	}
+}
+
+func TestParseMass(t *testing.T) {
+	var tests = []struct {
+		s       string
+		unit    string
+		wantLb  float32
+		wantErr error
+	}{
+		{s: "1.0", unit: "lb", wantLb: 1.0},
+		{s: "8", unit: "oz", wantLb: 0.5},
+		{s: "1", unit: "kg", wantLb: 2.204623},
+		{s: "453.59237", unit: "g", wantLb: 1.0},
+		{s: "abc", unit: "lb", wantErr: fmt.Errorf("strconv.ParseFloat: parsing \"abc\": invalid syntax")},
+	}
+	for _, test := range tests {
+		m, err := ParseMass(test.s, test.unit)
+		if fmt.Sprint(err) != fmt.Sprint(test.wantErr) {
+			t.Errorf("FAIL - %s %s: %v; want: %v", test.s, test.unit, err, test.wantErr)
+			continue
+		}
+		if math.Abs(float64(m.Pounds()-test.wantLb)) > 0.0001 {
+			t.Errorf("FAIL - %s %s: %f lb; want: %f", test.s, test.unit, m.Pounds(), test.wantLb)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	l, _ := (3 * Inch).Format("cm")
+	if l != "7.62" {
+		t.Errorf("FAIL - length: %s; want: 7.62", l)
+	}
+	m, _ := (2 * Pound).Format("oz")
+	if m != "32.00" {
+		t.Errorf("FAIL - mass: %s; want: 32.00", m)
+	}
+	if v := Cube(12*Inch, 12*Inch, 12*Inch).In(CubicFoot); math.Abs(float64(v-1.0)) > 0.0001 {
+		t.Errorf("FAIL - volume: %f cu ft; want: 1.0", v)
+	}
+}